@@ -20,8 +20,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/tetratelabs/run"
@@ -32,7 +32,9 @@ import (
 	"github.com/dio/rundown/generated/authservice/config"
 	"github.com/dio/rundown/internal/archives"
 	"github.com/dio/rundown/internal/downloader"
+	"github.com/dio/rundown/internal/listener"
 	"github.com/dio/rundown/internal/managed"
+	"github.com/dio/rundown/internal/output"
 	"github.com/dio/rundown/internal/runner"
 )
 
@@ -56,11 +58,16 @@ func New(g *run.Group, cfg *Config) *Service {
 	if cfg == nil {
 		cfg = &Config{} // TODO(dio): Have a way to generate default config.
 	}
+	if cfg.Logger == nil {
+		cfg.Logger = telemetry.NoopLogger()
+	}
 	return &Service{
 		cfg:     cfg,
 		g:       g,
 		archive: &archives.ExtAuthz{},
 		managed: &managed.Flags{
+			Supervised:     true,
+			Captured:       true,
 			DefaultVersion: DefaultBinaryVersion,
 		},
 	}
@@ -72,7 +79,13 @@ type Service struct {
 	g       *run.Group
 	archive *archives.ExtAuthz
 	managed *managed.Flags
-	cmd     *exec.Cmd
+
+	supervisor *managed.Supervisor
+	output     *output.Output
+	process    *runner.Process
+
+	mu       sync.Mutex
+	stopping bool
 }
 
 var _ run.Config = (*Service)(nil)
@@ -127,7 +140,13 @@ func (s *Service) Validate() error {
 	if s.cfg.FilterConfig == nil {
 		return errors.New("auth service config is required")
 	}
-	return s.cfg.FilterConfig.ValidateAll()
+	if err := s.cfg.FilterConfig.ValidateAll(); err != nil {
+		return err
+	}
+
+	var err error
+	s.supervisor, err = s.managed.Supervisor(s.Name(), s.cfg.Logger, nil)
+	return err
 }
 
 // PreRun prepares the binary to run.
@@ -172,23 +191,70 @@ func (s *Service) PreRun() (err error) {
 	}
 
 	configPath := tmp.Name() // effective config path.
-	s.cmd = runner.MakeCmd(binaryPath, []string{"--filter_config", configPath}, os.Stdout)
+
+	if s.output, err = output.New(s.Name(), s.cfg.Logger, s.managed.Output, nil); err != nil {
+		return err
+	}
+	s.process = runner.NewProcess(binaryPath, "--filter_config", configPath)
+	s.process.Stdout, s.process.Stderr = s.output.Stdout, s.output.Stderr
+	s.process.GraceTimeout = s.supervisor.Policy.GraceTimeout
 	return nil
 }
 
-// Serve runs the binary.
+// Serve runs the binary, and restarts it when it fails given the supervision policy.
 func (s *Service) Serve() error {
-	// Run the downloaded auth_server with the generated config in s.configPath.
-	if exitCode, err := runner.Run(s.cmd, s.archive); err != nil {
-		s.cfg.Logger.Error(fmt.Sprintf("%s exit with %d", s.archive.BinaryName(), exitCode), err)
+	defer s.output.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return s.supervisor.Run(func() error {
+		return s.serve(ctx)
+	}, s.process.Kill)
+}
+
+// GracefulStop stops the underlying process by sending interrupt, escalating to SIGTERM and SIGKILL
+// when it does not exit within the grace timeout.
+func (s *Service) GracefulStop() {
+	s.supervisor.Stop()
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+	if s.process != nil {
+		go s.process.Stop()
+	}
+}
+
+// serve runs the binary until it exits. Exiting with a non-zero code while not stopping is a failure.
+func (s *Service) serve(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		return nil
+	}
+	// Run the downloaded auth_server with the generated config.
+	err := s.process.Start(ctx)
+	s.mu.Unlock()
+	if err != nil {
 		return err
 	}
+
+	if err = s.process.Wait(); err != nil {
+		s.cfg.Logger.Error(fmt.Sprintf("%s exit with %d", s.archive.BinaryName(), s.process.ExitCode()), err)
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exitCode := s.process.ExitCode(); exitCode != 0 && !s.stopping {
+		return fmt.Errorf("%s exit with %d", s.archive.BinaryName(), exitCode)
+	}
 	return nil
 }
 
-// GracefulStop stops the underlying process by sending interrupt.
-func (s *Service) GracefulStop() {
-	if s.cmd != nil {
-		s.cmd.Process.Signal(os.Interrupt)
+// Address returns the address to dial the gRPC server of the service. It is empty when the service
+// is disabled. This is available once the service is validated.
+func (s *Service) Address() string {
+	if s.managed.IsDisabled() || s.cfg.FilterConfig == nil {
+		return ""
 	}
+	return listener.DialAddress(s.cfg.FilterConfig.ListenAddress, s.cfg.FilterConfig.ListenPort)
 }
@@ -0,0 +1,83 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dio/rundown/internal/envoy"
+)
+
+// ReadyPollInterval is the interval of checking whether the proxy is ready.
+var ReadyPollInterval = 200 * time.Millisecond
+
+// errNotLoaded is returned when the proxy config is not loaded yet.
+var errNotLoaded = errors.New("proxy config is not loaded")
+
+// Admin returns a client of the admin interface of the proxy, given the admin block of the proxy
+// config.
+func (s *Service) Admin() (*envoy.Admin, error) {
+	s.mu.Lock()
+	cfg := s.cfg.ProxyConfig
+	s.mu.Unlock()
+	if cfg == nil {
+		return nil, errNotLoaded
+	}
+	return envoy.NewAdmin(cfg)
+}
+
+// WaitReady blocks until the proxy is LIVE, or the context is done. It can be called before the
+// proxy is started.
+func (s *Service) WaitReady(ctx context.Context) error {
+	ticker := time.NewTicker(ReadyPollInterval)
+	defer ticker.Stop()
+	for {
+		admin, err := s.Admin()
+		if err != nil && !errors.Is(err, errNotLoaded) {
+			return err
+		}
+		if admin != nil {
+			var ready bool
+			var state string
+			if ready, state, err = admin.Ready(ctx); ready {
+				return nil
+			}
+			if err == nil {
+				err = fmt.Errorf("proxy state is %s", state)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("proxy is not ready: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeReady is the liveness probe that checks the proxy is LIVE.
+func (s *Service) probeReady(ctx context.Context) error {
+	admin, err := s.Admin()
+	if err != nil {
+		return err
+	}
+	ready, state, err := admin.Ready(ctx)
+	if err == nil && !ready {
+		err = fmt.Errorf("proxy state is %s", state)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+const (
+	// DefaultNodeID is the node ID of the default proxy config.
+	DefaultNodeID = "rundown"
+	// DefaultNodeCluster is the node cluster of the default proxy config.
+	DefaultNodeCluster = "rundown"
+	// DefaultAdminAddress is the admin address of the default proxy config.
+	DefaultAdminAddress = "127.0.0.1:9901"
+
+	// ExtAuthzClusterName is the name of the static cluster of the external auth service.
+	ExtAuthzClusterName = "ext-authz"
+	// RateLimitClusterName is the name of the static cluster of the rate limit service.
+	RateLimitClusterName = "rate-limit"
+)
+
+// defaultConfig returns the proxy config used when no config is given. It has only the admin
+// listener and the node, the rest is wired in PreRun based on the configured services.
+func (s *Service) defaultConfig() (*bootstrapv3.Bootstrap, error) {
+	address, err := resources.ParseAddress(s.adminAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &bootstrapv3.Bootstrap{
+		Node: &core.Node{
+			Id:      s.nodeID,
+			Cluster: s.nodeCluster,
+		},
+		Admin: &bootstrapv3.Admin{
+			Address: address,
+		},
+	}, nil
+}
+
+// addresser is implemented by the services that serve gRPC. An empty address means the service is
+// disabled.
+type addresser interface {
+	Address() string
+}
+
+// wire completes the proxy config with the configured services: the xDS service settings, and the
+// static clusters of the external auth and rate limit services. The services are validated at this
+// point, so their settings are available.
+func (s *Service) wire(b *bootstrapv3.Bootstrap) error {
+	// The xDS service is skipped when it is disabled.
+	if s.cfg.XDS != nil && s.cfg.XDS.Address() != "" {
+		// The node is wired to be a member of the node group, overriding it on the command line would
+		// serve the proxy with the resources of another node group.
+		if s.options.ServiceNode != "" || s.options.ServiceCluster != "" {
+			return fmt.Errorf("--%[1]s-service-node and --%[1]s-service-cluster can't be set when the %[1]s is wired to the xds service",
+				s.Name())
+		}
+		nodeGroup := s.cfg.XDSNodeGroup
+		if nodeGroup == "" {
+			nodeGroup = xds.DefaultNodeGroup
+		}
+		if err := s.cfg.XDS.ConfigureBootstrap(b, nodeGroup); err != nil {
+			return err
+		}
+	}
+
+	services := make(map[string]addresser, 2)
+	if s.cfg.Auth != nil {
+		services[ExtAuthzClusterName] = s.cfg.Auth
+	}
+	if s.cfg.RateLimit != nil {
+		services[RateLimitClusterName] = s.cfg.RateLimit
+	}
+	for clusterName, service := range services {
+		address := service.Address()
+		if address == "" {
+			continue // The service is disabled.
+		}
+		c, err := resources.GRPCCluster(clusterName, address)
+		if err != nil {
+			return err
+		}
+		setStaticCluster(b, c)
+	}
+	return nil
+}
+
+// setStaticCluster adds the cluster to the static resources, replacing the one with the same name.
+func setStaticCluster(b *bootstrapv3.Bootstrap, c *cluster.Cluster) {
+	if b.StaticResources == nil {
+		b.StaticResources = &bootstrapv3.Bootstrap_StaticResources{}
+	}
+	clusters := make([]*cluster.Cluster, 0, len(b.StaticResources.Clusters)+1)
+	for _, existing := range b.StaticResources.Clusters {
+		if existing.Name != c.Name {
+			clusters = append(clusters, existing)
+		}
+	}
+	b.StaticResources.Clusters = append(clusters, c)
+}
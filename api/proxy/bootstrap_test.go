@@ -0,0 +1,100 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/api/proxy"
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+type service string
+
+func (s service) Address() string {
+	return string(s)
+}
+
+func TestDefaultConfig(t *testing.T) {
+	s, dir := newService(t, &proxy.Config{}, "exit 0",
+		"--proxy-node-id", "node", "--proxy-node-cluster", "cluster", "--proxy-admin-address", "127.0.0.1:9000")
+	require.NoError(t, s.Validate())
+	require.NoError(t, s.PreRun())
+
+	cfg := writtenConfig(t, dir)
+	require.Equal(t, "node", cfg.Node.Id)
+	require.Equal(t, "cluster", cfg.Node.Cluster)
+	require.Equal(t, "127.0.0.1", cfg.Admin.Address.GetSocketAddress().GetAddress())
+	require.Equal(t, uint32(9000), cfg.Admin.Address.GetSocketAddress().GetPortValue())
+	require.Nil(t, cfg.DynamicResources)
+	require.Empty(t, cfg.StaticResources.GetClusters())
+
+	s, _ = newService(t, &proxy.Config{}, "exit 0", "--proxy-admin-address", "127.0.0.1")
+	require.Error(t, s.Validate())
+}
+
+func TestWire(t *testing.T) {
+	xdsService := xds.New(nil, &xds.Config{Config: &configv1.Config{
+		Host:       "0.0.0.0",
+		Port:       18000,
+		NodeGroups: []*configv1.NodeGroup{{Name: "edge", Match: &configv1.NodeMatch{Clusters: []string{"edge-proxy"}}}},
+	}})
+	s, dir := newService(t, &proxy.Config{
+		XDS:          xdsService,
+		XDSNodeGroup: "edge",
+		Auth:         service("127.0.0.1:10003"),
+		RateLimit:    service("unix:///tmp/ratelimit.sock"),
+	}, "exit 0")
+	require.NoError(t, s.Validate())
+	require.NoError(t, s.PreRun())
+
+	cfg := writtenConfig(t, dir)
+	require.NotNil(t, cfg.DynamicResources.GetAdsConfig())
+	require.Equal(t, resources.XDSClusterName,
+		cfg.DynamicResources.AdsConfig.GrpcServices[0].GetEnvoyGrpc().GetClusterName())
+	// The node of the default config is replaced to be a member of the node group.
+	require.Equal(t, "edge-proxy", cfg.Node.Cluster)
+
+	addresses := make(map[string]*core.Address)
+	for _, c := range cfg.StaticResources.Clusters {
+		addresses[c.Name] = c.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address
+		require.NotNil(t, c.GetTypedExtensionProtocolOptions(), c.Name) // gRPC requires HTTP/2.
+	}
+	require.Len(t, addresses, 3)
+	require.Equal(t, "127.0.0.1", addresses[resources.XDSClusterName].GetSocketAddress().GetAddress())
+	require.Equal(t, uint32(18000), addresses[resources.XDSClusterName].GetSocketAddress().GetPortValue())
+	require.Equal(t, uint32(10003), addresses[proxy.ExtAuthzClusterName].GetSocketAddress().GetPortValue())
+	require.Equal(t, "/tmp/ratelimit.sock", addresses[proxy.RateLimitClusterName].GetPipe().GetPath())
+
+	// The node wired to the node group can't be overridden on the command line.
+	for _, flag := range []string{"--proxy-service-node", "--proxy-service-cluster"} {
+		s, _ = newService(t, &proxy.Config{XDS: xdsService, XDSNodeGroup: "edge"}, "exit 0", flag, "other")
+		require.NoError(t, s.Validate())
+		err := s.PreRun()
+		require.Error(t, err, flag)
+		require.Contains(t, err.Error(), "--proxy-service-node and --proxy-service-cluster can't be set")
+	}
+
+	// The disabled services are skipped.
+	s, dir = newService(t, &proxy.Config{Auth: service(""), RateLimit: service("")}, "exit 0")
+	require.NoError(t, s.Validate())
+	require.NoError(t, s.PreRun())
+	require.Empty(t, writtenConfig(t, dir).StaticResources.GetClusters())
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// DefaultDrainPeriod is the default time to wait for the active connections to drain on graceful
+	// stop.
+	DefaultDrainPeriod = 10 * time.Second
+	// DrainPollInterval is the interval of checking the active connections while draining.
+	DrainPollInterval = 500 * time.Millisecond
+)
+
+// drain drains the proxy through its admin interface: the listeners are gracefully drained and the
+// health check is failed, then it waits for the drain period or until there is no active connection.
+// When the admin interface is not reachable, it returns right away.
+func (s *Service) drain() {
+	if s.drainPeriod <= 0 {
+		return
+	}
+	s.mu.Lock()
+	running := len(s.processes) > 0
+	s.mu.Unlock()
+	if !running {
+		return
+	}
+	admin, err := s.Admin()
+	if err != nil {
+		s.cfg.Logger.Debug("skip draining proxy", "reason", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainPeriod)
+	defer cancel()
+	if err = admin.DrainListeners(ctx, true); err != nil {
+		s.cfg.Logger.Error("failed to drain proxy listeners", err)
+		return
+	}
+	if err = admin.FailHealthcheck(ctx); err != nil {
+		s.cfg.Logger.Error("failed to fail proxy health check", err)
+	}
+
+	s.cfg.Logger.Info("draining proxy", "period", s.drainPeriod.String())
+	ticker := time.NewTicker(DrainPollInterval)
+	defer ticker.Stop()
+	for {
+		active, err := admin.ActiveConnections(ctx)
+		if err == nil && active == 0 {
+			s.cfg.Logger.Info("proxy drained")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			s.cfg.Logger.Info("proxy drain period elapsed", "active", active)
+			return
+		case <-ticker.C:
+		}
+	}
+}
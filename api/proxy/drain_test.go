@@ -0,0 +1,152 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+
+	"github.com/dio/rundown/api/proxy"
+)
+
+func TestDrain(t *testing.T) {
+	interval := proxy.DrainPollInterval
+	proxy.DrainPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() {
+		proxy.DrainPollInterval = interval
+	})
+
+	tests := []struct {
+		name   string
+		period time.Duration
+		// The active connections reported on each poll, the last one is repeated.
+		active []uint64
+		// The admin interface is not reachable.
+		unreachable bool
+		// The connections are drained before the drain period elapses.
+		drained bool
+		// The message logged once draining is done.
+		logged string
+	}{
+		{
+			name:    "connections drained",
+			period:  5 * time.Second,
+			active:  []uint64{2, 1, 0},
+			drained: true,
+			logged:  "proxy drained",
+		},
+		{
+			name:   "drain period elapsed",
+			period: 200 * time.Millisecond,
+			active: []uint64{1},
+			logged: "proxy drain period elapsed",
+		},
+		{
+			name:        "admin unreachable",
+			period:      5 * time.Second,
+			unreachable: true,
+			logged:      "failed to drain proxy listeners",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			admin := &fakeAdmin{active: test.active}
+			server := httptest.NewServer(admin)
+			address := server.Listener.Addr().String()
+			if test.unreachable {
+				server.Close()
+			} else {
+				t.Cleanup(server.Close)
+			}
+
+			logger := &recorder{Logger: telemetry.NoopLogger()}
+			s, dir := newService(t, &proxy.Config{Logger: logger}, "exec sleep 30",
+				"--proxy-admin-address", address, "--proxy-drain-period", test.period.String())
+			served := startProxy(t, s)
+			waitEpochs(t, dir, "0")
+
+			start := time.Now()
+			s.GracefulStop()
+			require.NoError(t, <-served)
+			elapsed := time.Since(start)
+
+			require.True(t, logger.logged(test.logged), test.logged)
+			calls := admin.recorded()
+			if test.unreachable {
+				// The proxy is stopped right away.
+				require.Empty(t, calls)
+				require.Less(t, int64(elapsed), int64(test.period))
+				return
+			}
+
+			// The listeners are drained and the health check is failed, then the active connections
+			// are polled.
+			require.GreaterOrEqual(t, len(calls), 3)
+			require.Equal(t, []string{"POST /drain_listeners?graceful", "POST /healthcheck/fail?"}, calls[:2])
+			for _, call := range calls[2:] {
+				require.Equal(t, "GET /stats?filter="+url.QueryEscape(`^listener\..*\.downstream_cx_active$`), call)
+			}
+			if test.drained {
+				require.Len(t, calls[2:], len(test.active))
+				require.Less(t, int64(elapsed), int64(test.period))
+			} else {
+				require.GreaterOrEqual(t, int64(elapsed), int64(test.period))
+			}
+		})
+	}
+}
+
+// fakeAdmin is a fake admin interface of the proxy, it records the calls other than the readiness
+// checks of the liveness probe.
+type fakeAdmin struct {
+	mu     sync.Mutex
+	calls  []string
+	active []uint64
+	polls  int
+}
+
+func (a *fakeAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/ready" {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+	if r.URL.Path != "/stats" {
+		return
+	}
+	active := a.active[len(a.active)-1]
+	if a.polls < len(a.active) {
+		active = a.active[a.polls]
+	}
+	a.polls++
+	// The admin listener connections are not counted.
+	_, _ = fmt.Fprintf(w, "listener.admin.downstream_cx_active: 1\nlistener.0.0.0.0_8080.downstream_cx_active: %d\n", active)
+}
+
+func (a *fakeAdmin) recorded() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.calls...)
+}
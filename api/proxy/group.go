@@ -16,12 +16,11 @@ package proxy
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 
 	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
@@ -31,10 +30,14 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"sigs.k8s.io/yaml"
 
+	"github.com/dio/rundown/api/xds"
 	"github.com/dio/rundown/internal/archives"
 	"github.com/dio/rundown/internal/downloader"
+	"github.com/dio/rundown/internal/envoy"
 	"github.com/dio/rundown/internal/managed"
+	"github.com/dio/rundown/internal/output"
 	"github.com/dio/rundown/internal/runner"
+	"github.com/dio/rundown/internal/watcher"
 )
 
 var (
@@ -49,13 +52,29 @@ type Config struct {
 	Logger         telemetry.Logger
 	ProxyConfig    *bootstrapv3.Bootstrap
 	GenerateConfig func() (*bootstrapv3.Bootstrap, error)
+	// XDS is the xDS service the proxy fetches its listeners and clusters from through ADS. When it
+	// is set, the dynamic_resources, the xds-grpc static cluster and the node of the proxy config are
+	// set to match the xDS service.
+	XDS *xds.Service
+	// XDSNodeGroup is the node group of the xDS service the proxy is a member of. Default:
+	// xds.DefaultNodeGroup.
+	XDSNodeGroup string
+	// Auth is the external auth service, e.g. an *auth.Service. When it is set, the static cluster
+	// named ExtAuthzClusterName is set to reach the service.
+	Auth addresser
+	// RateLimit is the rate limit service, e.g. a *ratelimit.Service. When it is set, the static
+	// cluster named RateLimitClusterName is set to reach the service.
+	RateLimit addresser
 }
 
 // New returns a new run.Service that wraps envoy binary. Setting the cfg to nil, expecting setting
-// the envoy's -c from a file.
+// the envoy's -c from a file. When no proxy config is given, a default one is generated.
 func New(g *run.Group, cfg *Config) *Service {
 	if cfg == nil {
-		cfg = &Config{} // TODO(dio): Have a way to generate default config.
+		cfg = &Config{}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = telemetry.NoopLogger()
 	}
 	return &Service{
 		cfg:     cfg,
@@ -63,6 +82,8 @@ func New(g *run.Group, cfg *Config) *Service {
 		archive: &archives.Proxy{},
 		managed: &managed.Flags{
 			DefaultVersion: DefaultBinaryVersion,
+			Supervised:     true,
+			Captured:       true,
 		},
 	}
 }
@@ -73,7 +94,30 @@ type Service struct {
 	g       *run.Group
 	archive *archives.Proxy
 	managed *managed.Flags
-	cmd     *exec.Cmd
+	watcher *watcher.Watcher
+
+	supervisor *managed.Supervisor
+	output     *output.Output
+
+	// The state of the running epochs, see restart.go.
+	mu         sync.Mutex
+	binaryPath string
+	configPath string
+	epoch      uint32
+	processes  map[uint32]*runner.Process
+	exits      chan exit
+	ctx        context.Context
+	stopping   bool
+
+	// The settings of the default proxy config.
+	nodeID       string
+	nodeCluster  string
+	adminAddress string
+
+	// The command line options of the proxy.
+	options     envoy.Options
+	hotRestart  bool
+	drainPeriod time.Duration
 }
 
 var _ run.Config = (*Service)(nil)
@@ -87,48 +131,56 @@ func (s *Service) Name() string {
 func (s *Service) FlagSet() *run.FlagSet {
 	flags := run.NewFlagSet("Proxy Service options")
 	s.managed.Manage(flags, s.g, s)
+	flags.StringVar(
+		&s.nodeID,
+		s.Name()+"-node-id",
+		DefaultNodeID,
+		"Node ID of the default proxy config, used when no proxy config is given")
+	flags.StringVar(
+		&s.nodeCluster,
+		s.Name()+"-node-cluster",
+		DefaultNodeCluster,
+		"Node cluster of the default proxy config, used when no proxy config is given")
+	flags.StringVar(
+		&s.adminAddress,
+		s.Name()+"-admin-address",
+		DefaultAdminAddress,
+		"Admin address (host:port or unix://path) of the default proxy config, used when no proxy config is given")
+	s.options.AddFlags(flags, s.Name())
+	flags.BoolVar(
+		&s.hotRestart,
+		s.Name()+"-hot-restart",
+		false,
+		"Hot restart the proxy every time its config file changes")
+	flags.DurationVar(
+		&s.drainPeriod,
+		s.Name()+"-drain-period",
+		DefaultDrainPeriod,
+		"Time to wait for the active connections to drain on graceful stop, through the admin interface. Zero stops the proxy right away")
 	return flags
 }
 
 // Validate validates the given configuration.
-func (s *Service) Validate() error {
+func (s *Service) Validate() (err error) {
 	if s.managed.IsDisabled() {
 		return nil
 	}
 
-	if s.cfg.GenerateConfig != nil {
-		generated, err := s.cfg.GenerateConfig()
-		if err != nil {
-			return err
-		}
-		s.cfg.ProxyConfig = generated
+	if err = s.options.Validate(); err != nil {
+		return fmt.Errorf("invalid %s options: %w", s.Name(), err)
 	}
-
-	if s.managed.ConfigFile != "" {
-		b, err := os.ReadFile(s.managed.ConfigFile)
-		if err != nil {
-			return err
-		}
-
-		// Probably a .yaml file. We simply check the extension here.
-		if filepath.Ext(s.managed.ConfigFile) == ".yaml" || filepath.Ext(s.managed.ConfigFile) == ".yml" {
-			b, err = yaml.YAMLToJSON(b)
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-		}
-
-		var cfg bootstrapv3.Bootstrap
-		if err = protojson.Unmarshal(b, &cfg); err != nil {
-			return err
-		}
-		s.cfg.ProxyConfig = &cfg
+	cfg, err := s.loadConfig()
+	if err != nil {
+		return err
 	}
+	s.mu.Lock()
+	s.cfg.ProxyConfig = cfg
+	s.mu.Unlock()
 
-	if s.cfg.ProxyConfig == nil {
-		return errors.New("proxy config is required")
-	}
-	return s.cfg.ProxyConfig.ValidateAll()
+	s.supervisor, err = s.managed.Supervisor(s.Name(), s.cfg.Logger, map[string]managed.Probe{
+		"ready": s.probeReady,
+	})
+	return err
 }
 
 // PreRun prepares the binary to run.
@@ -150,45 +202,176 @@ func (s *Service) PreRun() (err error) {
 	defer cancel()
 
 	// Check and download the versioned binary.
-	binaryPath, err := downloader.DownloadVersionedBinary(ctx, s.archive, s.managed.Dir)
-	if err != nil {
+	if s.binaryPath, err = downloader.DownloadVersionedBinary(ctx, s.archive, s.managed.Dir); err != nil {
 		return err
 	}
 
-	// Generate JSON config to run the proxy.
-	jsonConfig, err := protojson.Marshal(s.cfg.ProxyConfig)
-	if err != nil {
+	if s.configPath, err = s.writeConfig(s.cfg.ProxyConfig); err != nil {
 		return err
 	}
-
-	tmp, err := os.CreateTemp(s.managed.Dir, "*.json")
-	if err != nil {
-		return err
-	}
-	if _, err = tmp.Write(jsonConfig); err != nil {
+	if s.output, err = output.New(s.Name(), s.cfg.Logger, s.managed.Output, envoy.ParseLog); err != nil {
 		return err
 	}
 
-	configPath := tmp.Name() // effective config path.
-	// TODO(dio): Allow to execute with more options.
-	// Expose all envoy command line args as flags here.
-	s.cmd = runner.MakeCmd(binaryPath, []string{"-c", configPath}, os.Stdout)
+	// When hot restart is enabled, the proxy is hot restarted every time the config file changes.
+	if s.hotRestart && s.managed.ConfigFile != "" {
+		if s.watcher, err = watcher.New(s.managed.ConfigFile); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Serve runs the binary.
+// Serve runs the binary, and restarts it when it fails given the supervision policy.
 func (s *Service) Serve() error {
-	// Run the downloaded auth_server with the generated config in s.configPath.
-	if exitCode, err := runner.Run(s.cmd, s.archive); err != nil {
-		s.cfg.Logger.Error(fmt.Sprintf("%s exit with %d", s.archive.BinaryName(), exitCode), err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.output.Close()
+
+	s.mu.Lock()
+	s.exits = make(chan exit)
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	if s.watcher != nil {
+		go s.watcher.Run(ctx, func() {
+			if err := s.HotRestart(); err != nil {
+				s.cfg.Logger.Error("failed to hot restart proxy", err, "config", s.managed.ConfigFile)
+			}
+		}, func(err error) {
+			s.cfg.Logger.Error("failed to watch files", err, "config", s.managed.ConfigFile)
+		})
+	}
+	return s.supervisor.Run(s.serve, s.kill)
+}
+
+// serve starts the first epoch of the proxy, and returns when the latest epoch exits. Exiting with a
+// non-zero code while not stopping is a failure.
+func (s *Service) serve() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		return nil
+	}
+	s.epoch = 0
+	err := s.start(s.epoch, s.process(s.epoch, s.configPath))
+	s.mu.Unlock()
+	if err != nil {
+		s.cfg.Logger.Error(fmt.Sprintf("failed to start %s", s.archive.BinaryName()), err)
 		return err
 	}
-	return nil
+
+	go func() {
+		if err := s.WaitReady(ctx); err == nil {
+			s.cfg.Logger.Info("proxy is ready")
+		}
+	}()
+
+	for {
+		e := <-s.exits
+		if s.superseded(e) {
+			continue
+		}
+		if e.err != nil {
+			s.cfg.Logger.Error(fmt.Sprintf("%s exit with %d", s.archive.BinaryName(), e.code), e.err)
+			return e.err
+		}
+		s.mu.Lock()
+		stopping := s.stopping
+		s.mu.Unlock()
+		if e.code != 0 && !stopping {
+			return fmt.Errorf("%s exit with %d", s.archive.BinaryName(), e.code)
+		}
+		return nil
+	}
 }
 
-// GracefulStop stops the underlying process by sending interrupt.
+// GracefulStop drains the proxy, then stops the underlying processes by sending interrupt, escalating
+// to SIGTERM and SIGKILL when they do not exit within the grace timeout.
 func (s *Service) GracefulStop() {
-	if s.cmd != nil {
-		s.cmd.Process.Signal(os.Interrupt)
+	s.supervisor.Stop()
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+
+	s.drain()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.processes {
+		go p.Stop()
+	}
+}
+
+// loadConfig loads the proxy config. The config file takes precedence over the generated config,
+// which takes precedence over the given proxy config. When none of them is set, the default config
+// is used.
+func (s *Service) loadConfig() (*bootstrapv3.Bootstrap, error) {
+	cfg := s.cfg.ProxyConfig
+	if s.cfg.GenerateConfig != nil {
+		generated, err := s.cfg.GenerateConfig()
+		if err != nil {
+			return nil, err
+		}
+		cfg = generated
+	}
+
+	if s.managed.ConfigFile != "" {
+		b, err := os.ReadFile(s.managed.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+
+		// Probably a .yaml file. We simply check the extension here.
+		if filepath.Ext(s.managed.ConfigFile) == ".yaml" || filepath.Ext(s.managed.ConfigFile) == ".yml" {
+			b, err = yaml.YAMLToJSON(b)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load config: %w", err)
+			}
+		}
+
+		cfg = &bootstrapv3.Bootstrap{}
+		if err = protojson.Unmarshal(b, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg == nil {
+		generated, err := s.defaultConfig()
+		if err != nil {
+			return nil, err
+		}
+		cfg = generated
+	}
+	return cfg, cfg.ValidateAll()
+}
+
+// writeConfig wires the given proxy config to the configured services, and writes it as a JSON file
+// inside the work directory. It returns the path of the written file.
+func (s *Service) writeConfig(cfg *bootstrapv3.Bootstrap) (string, error) {
+	if err := s.wire(cfg); err != nil {
+		return "", err
+	}
+	if err := cfg.ValidateAll(); err != nil {
+		return "", err
+	}
+
+	// Generate JSON config to run the proxy.
+	jsonConfig, err := protojson.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(s.managed.Dir, "*.json")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+	if _, err = tmp.Write(jsonConfig); err != nil {
+		return "", err
 	}
+	return tmp.Name(), nil // effective config path.
 }
@@ -0,0 +1,74 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/dio/rundown/api/proxy"
+)
+
+// newService returns a proxy service running a fake envoy binary from its work directory, with the
+// given flags. The fake binary copies the config it is given to config.json in the work directory,
+// appends the started epochs to epochs, then runs the script with $epoch set. It returns the service
+// and its work directory.
+func newService(t *testing.T, cfg *proxy.Config, script string, args ...string) (*proxy.Service, string) {
+	dir := t.TempDir()
+	fake := `#!/bin/sh
+dir=$(dirname "$0")
+epoch=0
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -c) cp "$2" "$dir/config.json" ;;
+    --restart-epoch) epoch=$2 ;;
+  esac
+  shift
+done
+echo "$epoch" >> "$dir/epochs"
+` + script + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "envoy"), []byte(fake), 0o700)) //nolint:gosec
+
+	s := proxy.New(&run.Group{}, cfg)
+	require.NoError(t, s.FlagSet().Parse(append([]string{"--proxy-directory", dir}, args...)))
+	return s, dir
+}
+
+// writtenConfig returns the last proxy config written to the work directory.
+func writtenConfig(t *testing.T, dir string) *bootstrapv3.Bootstrap {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	var latest string
+	var modified int64
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		require.NoError(t, err)
+		if match != filepath.Join(dir, "config.json") && info.ModTime().UnixNano() >= modified {
+			latest, modified = match, info.ModTime().UnixNano()
+		}
+	}
+	require.NotEmpty(t, latest)
+	b, err := os.ReadFile(latest) //nolint:gosec
+	require.NoError(t, err)
+	cfg := &bootstrapv3.Bootstrap{}
+	require.NoError(t, protojson.Unmarshal(b, cfg))
+	return cfg
+}
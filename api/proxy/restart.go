@@ -0,0 +1,130 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dio/rundown/internal/runner"
+)
+
+// exit is the result of a proxy epoch.
+type exit struct {
+	epoch uint32
+	code  int
+	err   error
+}
+
+// HotRestart hot restarts the proxy: it reloads the proxy config, and starts a new epoch of the proxy
+// alongside the running one, with an incremented --restart-epoch and the same --base-id. The new epoch
+// takes over the listeners, while the previous one drains its connections (see --proxy-drain-time)
+// and exits after --proxy-parent-shutdown-time. When the new epoch fails, the previous one keeps
+// serving.
+func (s *Service) HotRestart() error {
+	cfg, err := s.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exits == nil || s.stopping {
+		return errors.New("proxy is not running")
+	}
+	configPath, err := s.writeConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if err = s.start(s.epoch+1, s.process(s.epoch+1, configPath)); err != nil {
+		return err
+	}
+	s.epoch++
+	s.configPath = configPath
+	s.cfg.ProxyConfig = cfg
+	s.cfg.Logger.Info("proxy hot restarted", "epoch", s.epoch)
+	return nil
+}
+
+// process returns the process to run the given epoch of the proxy with the given config.
+func (s *Service) process(epoch uint32, configPath string) *runner.Process {
+	args := []string{"-c", configPath}
+	if epoch > 0 {
+		args = append(args, "--restart-epoch", strconv.FormatUint(uint64(epoch), 10))
+	}
+	p := runner.NewProcess(s.binaryPath, append(args, s.options.Args()...)...)
+	p.Stdout, p.Stderr = s.output.Stdout, s.output.Stderr
+	p.GraceTimeout = s.supervisor.Policy.GraceTimeout
+	return p
+}
+
+// start starts the given epoch of the proxy, and reports its exit to s.exits. The epoch is stopped
+// when Serve returns. It must be called with s.mu held.
+func (s *Service) start(epoch uint32, p *runner.Process) error {
+	if err := p.Start(s.ctx); err != nil {
+		return err
+	}
+	if s.processes == nil {
+		s.processes = make(map[uint32]*runner.Process)
+	}
+	s.processes[epoch] = p
+	exits, done := s.exits, s.ctx.Done()
+	go func() {
+		err := p.Wait()
+		select {
+		case exits <- exit{epoch: epoch, code: p.ExitCode(), err: err}:
+		case <-done:
+		}
+	}()
+	return nil
+}
+
+// kill kills the running epochs.
+func (s *Service) kill() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.processes {
+		p.Kill()
+	}
+}
+
+// superseded forgets the exited epoch, and returns true when the proxy is still served by another
+// epoch: either the exited epoch is an old one, or it is a failed new one, hence its parent keeps
+// serving.
+func (s *Service) superseded(e exit) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.processes, e.epoch)
+	if e.epoch != s.epoch {
+		s.cfg.Logger.Info("proxy epoch exited", "epoch", e.epoch, "code", e.code)
+		return true
+	}
+	if s.stopping || len(s.processes) == 0 {
+		return false
+	}
+	// The next hot restart reuses the failed epoch number, since the new epoch is required to be the
+	// increment of the serving one.
+	var latest uint32
+	for epoch := range s.processes {
+		if epoch > latest {
+			latest = epoch
+		}
+	}
+	s.epoch = latest
+	s.cfg.Logger.Error("new proxy epoch exited, keep serving the previous one",
+		fmt.Errorf("%s exit with %d", s.archive.BinaryName(), e.code), "epoch", e.epoch)
+	return true
+}
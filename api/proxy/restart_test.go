@@ -0,0 +1,145 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+
+	"github.com/dio/rundown/api/proxy"
+)
+
+func TestHotRestart(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		// The message logged once the exit of an epoch is handled.
+		logged string
+		// The epochs started after the first hot restart, and after a second one.
+		started []string
+	}{
+		{
+			// The old epoch exits once the new one is started, as if its parent shutdown time elapsed.
+			name: "old epoch exits",
+			script: `if [ "$epoch" = 0 ]; then
+  until grep -qx 1 "$dir/epochs"; do sleep 0.01; done
+  exit 0
+fi
+exec sleep 30`,
+			logged:  "proxy epoch exited",
+			started: []string{"0", "1", "2"},
+		},
+		{
+			// The new epoch fails, the old one keeps serving, and the next hot restart retries the
+			// failed epoch number.
+			name: "new epoch fails",
+			script: `if [ "$epoch" = 1 ]; then exit 1; fi
+exec sleep 30`,
+			logged:  "new proxy epoch exited, keep serving the previous one",
+			started: []string{"0", "1", "1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := &recorder{Logger: telemetry.NoopLogger()}
+			s, dir := newService(t, &proxy.Config{Logger: logger}, test.script, "--proxy-drain-period", "0")
+			served := startProxy(t, s)
+			waitEpochs(t, dir, "0")
+
+			require.NoError(t, s.HotRestart())
+			waitEpochs(t, dir, test.started[:2]...)
+			require.Eventually(t, func() bool {
+				return logger.logged(test.logged)
+			}, 5*time.Second, 10*time.Millisecond)
+
+			// The proxy is still served.
+			select {
+			case err := <-served:
+				require.FailNow(t, "proxy stopped", err)
+			default:
+			}
+			require.NoError(t, s.HotRestart())
+			waitEpochs(t, dir, test.started...)
+
+			s.GracefulStop()
+			require.NoError(t, <-served)
+			require.Error(t, s.HotRestart())
+		})
+	}
+}
+
+func TestHotRestartNotRunning(t *testing.T) {
+	s, _ := newService(t, &proxy.Config{}, "exit 0")
+	require.NoError(t, s.Validate())
+	require.EqualError(t, s.HotRestart(), "proxy is not running")
+}
+
+// startProxy runs the proxy service, it returns the channel that receives the result of Serve.
+func startProxy(t *testing.T, s *proxy.Service) <-chan error {
+	require.NoError(t, s.Validate())
+	require.NoError(t, s.PreRun())
+	served := make(chan error, 1)
+	go func() {
+		served <- s.Serve()
+	}()
+	return served
+}
+
+// waitEpochs waits until the fake envoy binary has started exactly the given epochs, in order.
+func waitEpochs(t *testing.T, dir string, epochs ...string) {
+	expected := strings.Join(epochs, "\n") + "\n"
+	require.Eventually(t, func() bool {
+		b, err := os.ReadFile(filepath.Join(dir, "epochs")) //nolint:gosec
+		return err == nil && string(b) == expected
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// recorder records the logged messages.
+type recorder struct {
+	telemetry.Logger
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *recorder) Debug(msg string, _ ...interface{}) { r.record(msg) }
+
+func (r *recorder) Info(msg string, _ ...interface{}) { r.record(msg) }
+
+func (r *recorder) Error(msg string, _ error, _ ...interface{}) { r.record(msg) }
+
+func (r *recorder) record(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+}
+
+func (r *recorder) logged(msg string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.messages {
+		if m == msg {
+			return true
+		}
+	}
+	return false
+}
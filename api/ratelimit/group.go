@@ -17,17 +17,18 @@ package ratelimit
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 
 	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3" // added to resolve v3.HttpConnectionManager.
-	ratelimitrunner "github.com/envoyproxy/ratelimit/src/service_cmd/runner"
 	"github.com/tetratelabs/run"
 	"github.com/tetratelabs/telemetry"
 	"google.golang.org/protobuf/encoding/protojson"
 	"sigs.k8s.io/yaml"
 
 	settingsv1 "github.com/dio/rundown/generated/ratelimit/settings/v1"
+	"github.com/dio/rundown/internal/listener"
 	"github.com/dio/rundown/internal/managed"
 	"github.com/dio/rundown/internal/ratelimit"
 )
@@ -45,6 +46,9 @@ func New(g *run.Group, cfg *Config) *Service {
 	if cfg == nil {
 		cfg = &Config{} // TODO(dio): Have a way to generate default config.
 	}
+	if cfg.Logger == nil {
+		cfg.Logger = telemetry.NoopLogger()
+	}
 	return &Service{
 		cfg:     cfg,
 		g:       g,
@@ -56,8 +60,12 @@ func New(g *run.Group, cfg *Config) *Service {
 type Service struct {
 	cfg     *Config
 	g       *run.Group
-	runner  *ratelimitrunner.Runner
+	runner  *ratelimit.Runner
 	managed *managed.Flags
+
+	// The unix domain socket listener of the gRPC server, when the gRPC host is a unix domain socket
+	// address.
+	udsListener net.Listener
 }
 
 var _ run.Config = (*Service)(nil)
@@ -120,11 +128,18 @@ func (s *Service) PreRun() error {
 	if s.managed.IsDisabled() {
 		return nil
 	}
-	configured := ratelimit.NewSettings(s.cfg.Settings)
+	if address := s.cfg.Settings.GrpcHost.GetValue(); listener.IsUnix(address) {
+		mode, err := listener.ParseSocketMode(s.cfg.Settings.GrpcSocketMode.GetValue())
+		if err != nil {
+			return err
+		}
+		if s.udsListener, err = listener.Listen(address, mode); err != nil {
+			return err
+		}
+	}
 	// TODO(dio): Set the runtime path https://github.com/envoyproxy/ratelimit/blob/8d6488ead8618ce49a492858321dae946f2d97bc/src/settings/settings.go#L40-L43
 	// to be matched with configured work directory (e.g. via flag).
-	runner := ratelimitrunner.NewRunner(configured)
-	s.runner = &runner
+	s.runner = ratelimit.NewRunner(ratelimit.NewSettings(s.cfg.Settings), s.udsListener)
 	return nil
 }
 
@@ -145,4 +160,24 @@ func (s *Service) GracefulStop() {
 	if s.runner != nil {
 		s.runner.Stop()
 	}
+	if s.udsListener != nil {
+		_ = s.udsListener.Close()
+		if err := listener.Cleanup(s.cfg.Settings.GrpcHost.GetValue()); err != nil {
+			s.cfg.Logger.Error("failed to remove rate limit service socket file", err)
+		}
+	}
+}
+
+// Address returns the address to dial the gRPC server of the service, which is the unix domain socket
+// address when the gRPC host is one. It is empty when the service is disabled. This is available once
+// the service is validated.
+func (s *Service) Address() string {
+	if s.managed.IsDisabled() || s.cfg.Settings == nil {
+		return ""
+	}
+	if address := s.cfg.Settings.GrpcHost.GetValue(); listener.IsUnix(address) {
+		return address
+	}
+	configured := ratelimit.NewSettings(s.cfg.Settings)
+	return listener.DialAddress(configured.GrpcHost, int32(configured.GrpcPort))
 }
@@ -0,0 +1,85 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/dio/rundown/api/ratelimit"
+	settingsv1 "github.com/dio/rundown/generated/ratelimit/settings/v1"
+)
+
+func TestUnixAddress(t *testing.T) {
+	dir := t.TempDir()
+	runtime := filepath.Join(dir, "runtime")
+	require.NoError(t, os.MkdirAll(filepath.Join(runtime, "config"), 0o750))
+	socket := filepath.Join(dir, "ratelimit.sock")
+
+	s := ratelimit.New(&run.Group{}, &ratelimit.Config{
+		Logger: telemetry.NoopLogger(),
+		Settings: &settingsv1.Settings{
+			Host:             &wrapperspb.StringValue{Value: "127.0.0.1"},
+			Port:             &wrapperspb.UInt32Value{Value: 0},
+			GrpcHost:         &wrapperspb.StringValue{Value: "unix://" + socket},
+			GrpcSocketMode:   &wrapperspb.StringValue{Value: "0660"},
+			DebugHost:        &wrapperspb.StringValue{Value: "127.0.0.1"},
+			DebugPort:        &wrapperspb.UInt32Value{Value: 0},
+			UseStatsd:        &wrapperspb.BoolValue{Value: false},
+			RuntimePath:      &wrapperspb.StringValue{Value: runtime},
+			BackendType:      &wrapperspb.StringValue{Value: "memcache"},
+			MemcacheHostPort: []string{"127.0.0.1:11211"},
+		},
+	})
+	require.NoError(t, s.Validate())
+	require.Equal(t, "unix://"+socket, s.Address())
+	require.NoError(t, s.PreRun())
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- s.Serve()
+	}()
+
+	conn, err := grpc.Dial(s.Address(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	// The gRPC server is served directly on the unix domain socket.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	s.GracefulStop()
+	require.NoError(t, <-served)
+	_, err = os.Stat(socket)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cache "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/dio/rundown/internal/xds"
+)
+
+// Status is the status of the xDS server.
+type Status struct {
+	// Connected nodes.
+	Nodes []NodeStatus `json:"nodes"`
+	// Snapshot versions keyed by node group names, then by type URLs.
+	Snapshots map[string]map[string]string `json:"snapshots"`
+}
+
+// NodeStatus is the status of a connected node.
+type NodeStatus struct {
+	ID        string `json:"id"`
+	Cluster   string `json:"cluster"`
+	NodeGroup string `json:"node_group"`
+	Streams   int    `json:"streams"`
+	// The served snapshot versions, keyed by type URLs.
+	Served map[string]string `json:"served"`
+	// The ACKed versions, keyed by type URLs.
+	Acked    map[string]string `json:"acked"`
+	LastAck  *time.Time        `json:"last_ack,omitempty"`
+	LastNack *NackStatus       `json:"last_nack,omitempty"`
+}
+
+// NackStatus is the details of a rejected response.
+type NackStatus struct {
+	TypeURL string    `json:"type_url"`
+	Version string    `json:"version"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// Status returns the current status of the xDS server.
+func (s *Service) Status() (*Status, error) {
+	if s.callbacks == nil {
+		return nil, ErrNotReady
+	}
+
+	status := &Status{
+		Nodes:     s.callbacks.nodeStatuses(),
+		Snapshots: make(map[string]map[string]string),
+	}
+	for _, group := range s.nodeGroups.Names() {
+		snapshot, err := s.Snapshot(group)
+		if err != nil {
+			continue // The node group has no snapshot.
+		}
+		versions := make(map[string]string)
+		for _, typeURL := range typeURLs() {
+			if len(snapshot.GetResources(typeURL)) > 0 {
+				versions[typeURL] = snapshot.GetVersion(typeURL)
+			}
+		}
+		status.Snapshots[group] = versions
+	}
+
+	for i := range status.Nodes {
+		node := &status.Nodes[i]
+		node.Served = status.Snapshots[node.NodeGroup]
+	}
+	return status, nil
+}
+
+// adminHandler returns the handler of the admin HTTP server.
+func (s *Service) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		status, err := s.Status()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, status)
+	})
+	mux.HandleFunc("/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		dump, err := s.dumpSnapshots()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, dump)
+	})
+	mux.HandleFunc("/runtime", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, s.runtimeOverrides())
+		case http.MethodPost:
+			if !s.cfg.Config.Admin.AllowRuntimeUpdates {
+				http.Error(w, "runtime updates are not allowed", http.StatusForbidden)
+				return
+			}
+			var values map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			nodeGroup := r.URL.Query().Get("node_group")
+			if nodeGroup == "" {
+				nodeGroup = DefaultNodeGroup
+			}
+			if err := s.SetRuntime(nodeGroup, r.URL.Query().Get("layer"), values); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, s.runtimeOverrides())
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
+
+// dumpSnapshots returns the resources of the current snapshots, keyed by node group names, then by
+// type URLs. Each resource is marshaled as an Any, hence it has the "@type" field. The key material of
+// the secrets is redacted.
+func (s *Service) dumpSnapshots() (map[string]map[string][]json.RawMessage, error) {
+	dump := make(map[string]map[string][]json.RawMessage)
+	for _, group := range s.nodeGroups.Names() {
+		snapshot, err := s.Snapshot(group)
+		if err != nil {
+			continue // The node group has no snapshot.
+		}
+		resources := make(map[string][]json.RawMessage)
+		for _, typeURL := range typeURLs() {
+			named := snapshot.GetResources(typeURL)
+			names := make([]string, 0, len(named))
+			for name := range named {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				wrapped, err := anypb.New(xds.Redact(named[name]))
+				if err != nil {
+					return nil, err
+				}
+				b, err := protojson.Marshal(wrapped)
+				if err != nil {
+					return nil, err
+				}
+				resources[typeURL] = append(resources[typeURL], b)
+			}
+		}
+		dump[group] = resources
+	}
+	return dump, nil
+}
+
+// typeURLs returns all of the supported resource type URLs.
+func typeURLs() []string {
+	urls := make([]string, 0, types.UnknownType)
+	for i := types.ResponseType(0); i < types.UnknownType; i++ {
+		typeURL, err := cache.GetResponseTypeURL(i)
+		if err != nil {
+			continue
+		}
+		urls = append(urls, typeURL)
+	}
+	return urls
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/testutil"
+)
+
+func TestAdminSnapshotsRedactsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "tls.key")
+	testutil.WriteKeyPair(t, filepath.Join(dir, "tls.crt"), keyFile, 1)
+	key, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	_, admin := startService(t, &configv1.Config{
+		Resources: &configv1.Resources{FileSecrets: []*configv1.FileSecret{{Name: "server", Dir: dir}}},
+	})
+	dump := admin.get("/snapshots")
+	require.Contains(t, dump, `"name": "server"`)
+	require.Contains(t, dump, "[redacted]")
+	// The inlined bytes are marshaled as base64.
+	require.NotContains(t, dump, base64.StdEncoding.EncodeToString(key))
+}
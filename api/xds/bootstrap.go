@@ -0,0 +1,115 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"errors"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dio/rundown/api/xds/resources"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/listener"
+	"github.com/dio/rundown/internal/xds"
+)
+
+// Address returns the address to dial the xDS gRPC server. It is empty when the service is disabled.
+// This is available once the service is validated.
+func (s *Service) Address() string {
+	if s.managed.IsDisabled() || s.cfg.Config == nil {
+		return ""
+	}
+	return listener.DialAddress(s.cfg.Config.Host, s.cfg.Config.Port)
+}
+
+// ConfigureBootstrap sets the bootstrap of a proxy to fetch its listeners and clusters from this
+// service through ADS, over delta streams when delta xDS is enabled. The dynamic_resources and the
+// static cluster named resources.XDSClusterName of the bootstrap are replaced. When the bootstrap
+// node is not a member of the given node group, the node is replaced by one that is. This needs to
+// be called after the service is validated.
+func (s *Service) ConfigureBootstrap(b *bootstrapv3.Bootstrap, nodeGroup string) error {
+	cfg := s.cfg.Config
+	if cfg == nil || s.managed.IsDisabled() {
+		return ErrNotReady
+	}
+	if cfg.Tls != nil {
+		return errors.New("configuring a bootstrap for the xds service with TLS is not supported")
+	}
+	if len(cfg.Services) > 0 && !hasDiscoveryService(cfg.Services, configv1.DiscoveryService_DISCOVERY_SERVICE_ADS) {
+		return errors.New("the xds service does not serve ADS")
+	}
+
+	groups, err := xds.NewNodeGroups(cfg.NodeGroups)
+	if err != nil {
+		return err
+	}
+	if b.Node == nil || groups.ID(b.Node) != nodeGroup {
+		node, err := xds.NewNode(cfg, nodeGroup)
+		if err != nil {
+			return err
+		}
+		b.Node = node
+	}
+
+	// The delta streams are only served when delta xDS is enabled.
+	apiType := core.ApiConfigSource_GRPC
+	if cfg.Delta {
+		apiType = core.ApiConfigSource_DELTA_GRPC
+	}
+	source := resources.ADSConfigSource()
+	b.DynamicResources = &bootstrapv3.Bootstrap_DynamicResources{
+		LdsConfig: source,
+		CdsConfig: proto.Clone(source).(*core.ConfigSource),
+		AdsConfig: &core.ApiConfigSource{
+			ApiType:                   apiType,
+			TransportApiVersion:       resource.DefaultAPIVersion,
+			SetNodeOnFirstMessageOnly: true,
+			GrpcServices: []*core.GrpcService{{
+				TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &core.GrpcService_EnvoyGrpc{ClusterName: resources.XDSClusterName},
+				},
+			}},
+		},
+	}
+
+	if b.StaticResources == nil {
+		b.StaticResources = &bootstrapv3.Bootstrap_StaticResources{}
+	}
+	clusters := make([]*cluster.Cluster, 0, len(b.StaticResources.Clusters)+1)
+	for _, c := range b.StaticResources.Clusters {
+		if c.Name != resources.XDSClusterName {
+			clusters = append(clusters, c)
+		}
+	}
+	xdsCluster, err := resources.GRPCCluster(resources.XDSClusterName, s.Address())
+	if err != nil {
+		return err
+	}
+	b.StaticResources.Clusters = append(clusters, xdsCluster)
+	return nil
+}
+
+func hasDiscoveryService(services []configv1.DiscoveryService, service configv1.DiscoveryService) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"testing"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+func TestConfigureBootstrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *configv1.Config
+		apiType core.ApiConfigSource_ApiType
+		err     string
+	}{
+		{name: "sotw", cfg: &configv1.Config{}, apiType: core.ApiConfigSource_GRPC},
+		{name: "delta", cfg: &configv1.Config{Delta: true}, apiType: core.ApiConfigSource_DELTA_GRPC},
+		{
+			name: "ads only",
+			cfg: &configv1.Config{Services: []configv1.DiscoveryService{
+				configv1.DiscoveryService_DISCOVERY_SERVICE_ADS,
+			}},
+			apiType: core.ApiConfigSource_GRPC,
+		},
+		{
+			name: "no ads",
+			cfg: &configv1.Config{Services: []configv1.DiscoveryService{
+				configv1.DiscoveryService_DISCOVERY_SERVICE_CDS,
+			}},
+			err: "the xds service does not serve ADS",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.cfg.Host, test.cfg.Port = "127.0.0.1", 18000
+			s := xds.New(&run.Group{}, &xds.Config{Config: test.cfg})
+			require.NoError(t, s.Validate())
+
+			b := &bootstrapv3.Bootstrap{}
+			err := s.ConfigureBootstrap(b, xds.DefaultNodeGroup)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, b.ValidateAll())
+			ads := b.DynamicResources.AdsConfig
+			require.Equal(t, test.apiType, ads.ApiType)
+			require.Equal(t, resources.XDSClusterName, ads.GrpcServices[0].GetEnvoyGrpc().ClusterName)
+			require.NotNil(t, b.DynamicResources.LdsConfig.GetAds())
+			require.NotNil(t, b.DynamicResources.CdsConfig.GetAds())
+			require.Len(t, b.StaticResources.Clusters, 1)
+			require.Equal(t, resources.XDSClusterName, b.StaticResources.Clusters[0].Name)
+		})
+	}
+
+	// Not validated yet.
+	s := xds.New(&run.Group{}, &xds.Config{})
+	require.ErrorIs(t, s.ConfigureBootstrap(&bootstrapv3.Bootstrap{}, xds.DefaultNodeGroup), xds.ErrNotReady)
+}
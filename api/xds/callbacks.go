@@ -0,0 +1,301 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	cache "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// logger adapts telemetry.Logger to the logger used by go-control-plane.
+type logger struct {
+	telemetry.Logger
+}
+
+func (l logger) Debugf(format string, args ...interface{}) {
+	l.Logger.Debug(fmt.Sprintf(format, args...))
+}
+func (l logger) Infof(format string, args ...interface{}) {
+	l.Logger.Info(fmt.Sprintf(format, args...))
+}
+
+// Warnf logs at the info level, marked as a warning, since telemetry.Logger has no warning level.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.Logger.Info(fmt.Sprintf(format, args...), "warning", true)
+}
+
+// Errorf logs the formatted message as the error.
+func (l logger) Errorf(format string, args ...interface{}) {
+	l.Logger.Error("xds cache error", fmt.Errorf(format, args...))
+}
+
+// adsStreamType is the type of the ADS streams, which are opened with an empty type URL since they
+// carry resources of any type.
+const adsStreamType = "ads"
+
+// stream holds the state of an open xDS stream.
+type stream struct {
+	typeURL string
+	delta   bool
+	node    *core.Node
+	sent    map[string]string // versions of the sent responses, keyed by nonce.
+}
+
+// nack holds the details of a rejected response.
+type nack struct {
+	typeURL string
+	version string
+	message string
+	at      time.Time
+}
+
+// nodeStatus holds what a connected node has accepted or rejected.
+type nodeStatus struct {
+	node     *core.Node
+	streams  int
+	acked    map[string]string // ACKed versions, keyed by type URL.
+	lastAck  time.Time
+	lastNack *nack
+}
+
+// callbacks observes the xDS server streams. It logs and records metrics of the streams, requests,
+// responses, ACKs and NACKs, and tracks the status of the connected nodes.
+type callbacks struct {
+	delta   bool // when false, delta streams are rejected.
+	hash    cache.NodeHash
+	logger  telemetry.Logger
+	metrics *metrics
+
+	mu      sync.Mutex
+	streams map[int64]*stream
+	nodes   map[string]*nodeStatus // keyed by node ID.
+	open    map[string]int         // number of open streams, keyed by type URL.
+}
+
+var _ server.Callbacks = (*callbacks)(nil)
+
+func newCallbacks(logger telemetry.Logger, hash cache.NodeHash, delta bool) *callbacks {
+	return &callbacks{
+		delta:   delta,
+		hash:    hash,
+		logger:  logger,
+		metrics: newMetrics(),
+		streams: make(map[int64]*stream),
+		nodes:   make(map[string]*nodeStatus),
+		open:    make(map[string]int),
+	}
+}
+
+func (c *callbacks) Report() {}
+
+func (c *callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error {
+	c.openStream(id, typ, false)
+	return nil
+}
+
+func (c *callbacks) OnStreamClosed(id int64) {
+	c.closeStream(id)
+}
+
+func (c *callbacks) OnDeltaStreamOpen(_ context.Context, id int64, typ string) error {
+	if !c.delta {
+		return status.Error(codes.Unimplemented, "delta xDS is disabled")
+	}
+	c.openStream(id, typ, true)
+	return nil
+}
+
+func (c *callbacks) OnDeltaStreamClosed(id int64) {
+	c.closeStream(id)
+}
+
+func (c *callbacks) OnStreamRequest(id int64, req *discoveryservice.DiscoveryRequest) error {
+	c.onRequest(id, req.GetNode(), req.GetTypeUrl(), req.GetResponseNonce(), req.GetErrorDetail() != nil,
+		req.GetErrorDetail().GetMessage())
+	return nil
+}
+
+func (c *callbacks) OnStreamResponse(_ context.Context, id int64, _ *discoveryservice.DiscoveryRequest,
+	res *discoveryservice.DiscoveryResponse) {
+	c.onResponse(id, res.GetTypeUrl(), res.GetVersionInfo(), res.GetNonce())
+}
+
+func (c *callbacks) OnStreamDeltaRequest(id int64, req *discoveryservice.DeltaDiscoveryRequest) error {
+	c.onRequest(id, req.GetNode(), req.GetTypeUrl(), req.GetResponseNonce(), req.GetErrorDetail() != nil,
+		req.GetErrorDetail().GetMessage())
+	return nil
+}
+
+func (c *callbacks) OnStreamDeltaResponse(id int64, _ *discoveryservice.DeltaDiscoveryRequest,
+	res *discoveryservice.DeltaDiscoveryResponse) {
+	c.onResponse(id, res.GetTypeUrl(), res.GetSystemVersionInfo(), res.GetNonce())
+}
+
+func (c *callbacks) OnFetchRequest(_ context.Context, req *discoveryservice.DiscoveryRequest) error {
+	c.metrics.increment(requests, req.GetTypeUrl())
+	return nil
+}
+
+func (c *callbacks) OnFetchResponse(_ *discoveryservice.DiscoveryRequest, res *discoveryservice.DiscoveryResponse) {
+	c.metrics.increment(responses, res.GetTypeUrl())
+}
+
+func (c *callbacks) openStream(id int64, typeURL string, delta bool) {
+	if typeURL == resource.AnyType {
+		typeURL = adsStreamType
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.streams[id] = &stream{typeURL: typeURL, delta: delta, sent: make(map[string]string)}
+	c.open[typeURL]++
+	c.metrics.record(openStreams, typeURL, float64(c.open[typeURL]))
+	c.logger.Debug("xds stream opened", "stream", id, "type_url", typeURL, "delta", delta)
+}
+
+func (c *callbacks) closeStream(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.streams[id]
+	if !ok {
+		return
+	}
+	delete(c.streams, id)
+	c.open[s.typeURL]--
+	c.metrics.record(openStreams, s.typeURL, float64(c.open[s.typeURL]))
+
+	if s.node != nil {
+		if n, ok := c.nodes[s.node.Id]; ok {
+			n.streams--
+			if n.streams <= 0 {
+				delete(c.nodes, s.node.Id)
+				c.logger.Info("xds node disconnected", "node", s.node.Id, "cluster", s.node.Cluster)
+			}
+		}
+	}
+	c.logger.Debug("xds stream closed", "stream", id, "type_url", s.typeURL, "node", s.node.GetId())
+}
+
+// onRequest handles a request. A request with a nonce is a reply to a sent response, it is either
+// an ACK or a NACK (rejected, with an error detail message).
+func (c *callbacks) onRequest(id int64, node *core.Node, typeURL, nonce string, rejected bool, message string) {
+	c.metrics.increment(requests, typeURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.streams[id]
+	if !ok {
+		return
+	}
+	// The node is possibly only set on the first request of a stream.
+	if s.node == nil && node != nil {
+		s.node = node
+		n, ok := c.nodes[node.Id]
+		if !ok {
+			n = &nodeStatus{acked: make(map[string]string)}
+			c.nodes[node.Id] = n
+			c.logger.Info("xds node connected", "node", node.Id, "cluster", node.Cluster)
+		}
+		n.node = node
+		n.streams++
+	}
+	// An initial request, or a request that is not a reply to a response.
+	if nonce == "" {
+		return
+	}
+
+	version := s.sent[nonce]
+	delete(s.sent, nonce)
+	n, ok := c.nodes[s.node.GetId()]
+	if !ok {
+		return
+	}
+
+	if rejected {
+		c.metrics.increment(nacks, typeURL)
+		n.lastNack = &nack{typeURL: typeURL, version: version, message: message, at: time.Now()}
+		c.logger.Error("xds response rejected", errors.New(message),
+			"node", n.node.Id, "type_url", typeURL, "version", version)
+		return
+	}
+
+	c.metrics.increment(acks, typeURL)
+	n.acked[typeURL] = version
+	n.lastAck = time.Now()
+	c.logger.Debug("xds response accepted", "node", n.node.Id, "type_url", typeURL, "version", version)
+}
+
+func (c *callbacks) onResponse(id int64, typeURL, version, nonce string) {
+	c.metrics.increment(responses, typeURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.streams[id]; ok {
+		s.sent[nonce] = version
+	}
+	c.logger.Debug("xds response sent", "stream", id, "type_url", typeURL, "version", version)
+}
+
+// nodeStatuses returns the status of the connected nodes, sorted by their IDs.
+func (c *callbacks) nodeStatuses() []NodeStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]NodeStatus, 0, len(c.nodes))
+	for id, n := range c.nodes {
+		status := NodeStatus{
+			ID:        id,
+			Cluster:   n.node.GetCluster(),
+			NodeGroup: c.hash.ID(n.node),
+			Streams:   n.streams,
+			Acked:     make(map[string]string, len(n.acked)),
+		}
+		for typeURL, version := range n.acked {
+			status.Acked[typeURL] = version
+		}
+		if !n.lastAck.IsZero() {
+			lastAck := n.lastAck
+			status.LastAck = &lastAck
+		}
+		if n.lastNack != nil {
+			status.LastNack = &NackStatus{
+				TypeURL: n.lastNack.typeURL,
+				Version: n.lastNack.version,
+				Message: n.lastNack.message,
+				At:      n.lastNack.at,
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses
+}
@@ -0,0 +1,254 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/genproto/googleapis/rpc/status"
+
+	"github.com/dio/rundown/api/xds"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+func TestCallbacks(t *testing.T) {
+	sink := newFakeSink()
+	telemetry.SetGlobalMetricSink(sink)
+
+	socket := filepath.Join(t.TempDir(), "xds.sock")
+	s, _ := startService(t, &configv1.Config{
+		Host:      "unix://" + socket,
+		Resources: runtimeResources(t, "foo", "bar"),
+	})
+	conn := dial(t, socket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ads, err := discoveryservice.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	require.NoError(t, err)
+	node := &core.Node{Id: "node-1", Cluster: "edge"}
+	require.NoError(t, ads.Send(&discoveryservice.DiscoveryRequest{
+		Node:          node,
+		TypeUrl:       resource.RuntimeType,
+		ResourceNames: []string{"rtds"},
+	}))
+	res, err := ads.Recv()
+	require.NoError(t, err)
+	require.Equal(t, resource.RuntimeType, res.TypeUrl)
+
+	// The ADS streams are recorded with the "ads" type.
+	require.Eventually(t, func() bool {
+		return sink.value("xds_open_streams", "ads") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(0), sink.value("xds_open_streams", ""))
+
+	// The node is connected, but nothing is ACKed yet.
+	nodes := nodeStatuses(t, s)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "node-1", nodes[0].ID)
+	require.Equal(t, "edge", nodes[0].Cluster)
+	require.Equal(t, xds.DefaultNodeGroup, nodes[0].NodeGroup)
+	require.Equal(t, 1, nodes[0].Streams)
+	require.Empty(t, nodes[0].Acked)
+	require.Nil(t, nodes[0].LastAck)
+
+	// ACK.
+	require.NoError(t, ads.Send(&discoveryservice.DiscoveryRequest{
+		TypeUrl:       resource.RuntimeType,
+		ResourceNames: []string{"rtds"},
+		VersionInfo:   res.VersionInfo,
+		ResponseNonce: res.Nonce,
+	}))
+	require.Eventually(t, func() bool {
+		nodes := nodeStatuses(t, s)
+		return len(nodes) == 1 && nodes[0].Acked[resource.RuntimeType] == res.VersionInfo
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(t, nodeStatuses(t, s)[0].LastAck)
+	require.Equal(t, float64(1), sink.value("xds_acks_total", resource.RuntimeType))
+
+	// NACK the response of the next version.
+	require.NoError(t, s.SetRuntime(xds.DefaultNodeGroup, "rtds", map[string]interface{}{"foo": false}))
+	rejected, err := ads.Recv()
+	require.NoError(t, err)
+	require.NotEqual(t, res.VersionInfo, rejected.VersionInfo)
+	require.NoError(t, ads.Send(&discoveryservice.DiscoveryRequest{
+		TypeUrl:       resource.RuntimeType,
+		ResourceNames: []string{"rtds"},
+		VersionInfo:   res.VersionInfo,
+		ResponseNonce: rejected.Nonce,
+		ErrorDetail:   &status.Status{Message: "invalid runtime"},
+	}))
+	require.Eventually(t, func() bool {
+		nodes := nodeStatuses(t, s)
+		return len(nodes) == 1 && nodes[0].LastNack != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	nack := nodeStatuses(t, s)[0].LastNack
+	require.Equal(t, resource.RuntimeType, nack.TypeURL)
+	require.Equal(t, rejected.VersionInfo, nack.Version)
+	require.Equal(t, "invalid runtime", nack.Message)
+	// The ACKed version is kept.
+	require.Equal(t, res.VersionInfo, nodeStatuses(t, s)[0].Acked[resource.RuntimeType])
+	require.Equal(t, float64(1), sink.value("xds_nacks_total", resource.RuntimeType))
+
+	// The streams of a single type are recorded with their type URLs.
+	rtdsCtx, rtdsCancel := context.WithCancel(context.Background())
+	defer rtdsCancel()
+	rtds, err := runtimeservice.NewRuntimeDiscoveryServiceClient(conn).StreamRuntime(rtdsCtx)
+	require.NoError(t, err)
+	require.NoError(t, rtds.Send(&discoveryservice.DiscoveryRequest{Node: node, ResourceNames: []string{"rtds"}}))
+	_, err = rtds.Recv()
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return sink.value("xds_open_streams", resource.RuntimeType) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, nodeStatuses(t, s)[0].Streams)
+
+	// The node is disconnected once all of its streams are closed.
+	rtdsCancel()
+	cancel()
+	require.Eventually(t, func() bool {
+		return len(nodeStatuses(t, s)) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(0), sink.value("xds_open_streams", "ads"))
+	require.Equal(t, float64(0), sink.value("xds_open_streams", resource.RuntimeType))
+}
+
+func TestCacheLogger(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "xds.sock")
+	resources := runtimeResources(t, "version", "v1")
+	resources.RuntimeLayers = append(resources.RuntimeLayers,
+		&configv1.RuntimeLayer{Name: "other", Layer: resources.RuntimeLayers[0].Layer})
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	startServiceWith(t, &xds.Config{
+		Config: &configv1.Config{Host: "unix://" + socket, Ads: true, Resources: resources},
+		Logger: logger,
+	})
+
+	// In ADS mode, a request that does not list all of the resources is not responded, with a warning.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ads, err := discoveryservice.NewAggregatedDiscoveryServiceClient(dial(t, socket)).StreamAggregatedResources(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ads.Send(&discoveryservice.DiscoveryRequest{
+		Node:          &core.Node{Id: "test"},
+		TypeUrl:       resource.RuntimeType,
+		ResourceNames: []string{"rtds"},
+	}))
+	var warnings []entry
+	require.Eventually(t, func() bool {
+		warnings = logger.find(func(e entry) bool { return strings.HasPrefix(e.msg, "ADS mode: not responding") })
+		return len(warnings) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "info", warnings[0].level)
+	require.Equal(t, []interface{}{"warning", true}, warnings[0].kv)
+}
+
+func nodeStatuses(t *testing.T, s *xds.Service) []xds.NodeStatus {
+	status, err := s.Status()
+	require.NoError(t, err)
+	return status.Nodes
+}
+
+// fakeSink records the values of the metrics with a single label: the gauges keep the last value,
+// the others are summed.
+type fakeSink struct {
+	mu     sync.Mutex
+	values map[string]float64 // keyed by metric names, then by label values.
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{values: make(map[string]float64)}
+}
+
+func (s *fakeSink) value(name, label string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[name+"/"+label]
+}
+
+func (s *fakeSink) NewSum(name, _ string, _ ...telemetry.MetricOption) telemetry.Metric {
+	return &fakeMetric{sink: s, name: name}
+}
+
+func (s *fakeSink) NewGauge(name, _ string, _ ...telemetry.MetricOption) telemetry.Metric {
+	return &fakeMetric{sink: s, name: name, gauge: true}
+}
+
+func (s *fakeSink) NewDistribution(name, _ string, _ []float64, _ ...telemetry.MetricOption) telemetry.Metric {
+	return &fakeMetric{sink: s, name: name}
+}
+
+func (s *fakeSink) NewLabel(string) telemetry.Label {
+	return fakeLabel{}
+}
+
+func (s *fakeSink) ContextWithLabels(ctx context.Context, _ ...telemetry.LabelValue) (context.Context, error) {
+	return ctx, nil
+}
+
+type fakeMetric struct {
+	sink  *fakeSink
+	name  string
+	label string
+	gauge bool
+}
+
+func (m *fakeMetric) Increment() { m.Record(1) }
+func (m *fakeMetric) Decrement() { m.Record(-1) }
+func (m *fakeMetric) Name() string {
+	return m.name
+}
+
+func (m *fakeMetric) Record(value float64) {
+	m.sink.mu.Lock()
+	defer m.sink.mu.Unlock()
+
+	key := m.name + "/" + m.label
+	if !m.gauge {
+		value += m.sink.values[key]
+	}
+	m.sink.values[key] = value
+}
+
+func (m *fakeMetric) RecordContext(_ context.Context, value float64) {
+	m.Record(value)
+}
+
+func (m *fakeMetric) With(values ...telemetry.LabelValue) telemetry.Metric {
+	with := *m
+	for _, value := range values {
+		with.label = value.(string)
+	}
+	return &with
+}
+
+// fakeLabel values are the plain label values.
+type fakeLabel struct{}
+
+func (fakeLabel) Insert(value string) telemetry.LabelValue { return value }
+func (fakeLabel) Update(value string) telemetry.LabelValue { return value }
+func (fakeLabel) Upsert(value string) telemetry.LabelValue { return value }
+func (fakeLabel) Delete() telemetry.LabelValue             { return "" }
@@ -0,0 +1,160 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+// DefaultDNSRefreshInterval is the default interval of resolving the DNS names of a
+// DNSEndpointSource.
+const DefaultDNSRefreshInterval = 30 * time.Second
+
+// DNSTarget is a DNS name to be resolved as the endpoints of a cluster.
+type DNSTarget struct {
+	// Cluster is the name of the cluster.
+	Cluster string
+	// Name is resolved as SRV records when it is in the form of _service._proto.name, e.g.
+	// _http._tcp.backend.service.consul. Otherwise, it is resolved as A and AAAA records. The SRV
+	// priorities are mapped to the endpoint priorities, ranked from 0 in the ascending order, and the
+	// SRV weights to the endpoint load balancing weights.
+	Name string
+	// Port of the endpoints resolved from A and AAAA records.
+	Port uint32
+}
+
+// DNSEndpointSource is an endpoint source that resolves DNS names periodically.
+type DNSEndpointSource struct {
+	// Interval is the interval of resolving the names.
+	Interval time.Duration
+	// Resolver is used to resolve the names. When it is nil, net.DefaultResolver is used.
+	Resolver *net.Resolver
+
+	targets []DNSTarget
+}
+
+var _ EndpointSource = (*DNSEndpointSource)(nil)
+
+// NewDNSEndpointSource returns a new DNSEndpointSource that resolves the given targets every
+// DefaultDNSRefreshInterval.
+func NewDNSEndpointSource(targets ...DNSTarget) *DNSEndpointSource {
+	return &DNSEndpointSource{
+		Interval: DefaultDNSRefreshInterval,
+		targets:  targets,
+	}
+}
+
+// Endpoints resolves the targets. The endpoints of targets of the same cluster are combined.
+func (d *DNSEndpointSource) Endpoints(ctx context.Context) (map[string][]resources.Endpoint, error) {
+	endpoints := make(map[string][]resources.Endpoint, len(d.targets))
+	for _, target := range d.targets {
+		var (
+			resolved []resources.Endpoint
+			err      error
+		)
+		if isSRV(target.Name) {
+			resolved, err = d.lookupSRV(ctx, target.Name)
+		} else {
+			resolved, err = d.lookupIP(ctx, target.Name, target.Port)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", target.Name, err)
+		}
+		endpoints[target.Cluster] = append(endpoints[target.Cluster], resolved...)
+	}
+	return endpoints, nil
+}
+
+// Watch calls onChange every interval.
+func (d *DNSEndpointSource) Watch(ctx context.Context, onChange func(), _ func(error)) {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = DefaultDNSRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			onChange()
+		}
+	}
+}
+
+func (d *DNSEndpointSource) lookupSRV(ctx context.Context, name string) ([]resources.Endpoint, error) {
+	_, records, err := d.resolver().LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+	// The records are sorted by priority, the distinct priorities are ranked, since Envoy requires
+	// the priorities of a cluster to be contiguous.
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Priority < records[j].Priority
+	})
+	var (
+		endpoints []resources.Endpoint
+		rank      uint32
+	)
+	// The SRV targets are resolved to IP addresses, since an endpoint is required to be an IP address.
+	for i, record := range records {
+		if i > 0 && record.Priority != records[i-1].Priority {
+			rank++
+		}
+		resolved, err := d.lookupIP(ctx, record.Target, uint32(record.Port))
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resolved {
+			e.Priority = rank
+			e.Weight = uint32(record.Weight)
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints, nil
+}
+
+func (d *DNSEndpointSource) lookupIP(ctx context.Context, name string, port uint32) ([]resources.Endpoint, error) {
+	ips, err := d.resolver().LookupIP(ctx, "ip", name)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := make([]resources.Endpoint, 0, len(ips))
+	for _, ip := range ips {
+		endpoints = append(endpoints, resources.Endpoint{Host: ip.String(), Port: port})
+	}
+	return endpoints, nil
+}
+
+func (d *DNSEndpointSource) resolver() *net.Resolver {
+	if d.Resolver != nil {
+		return d.Resolver
+	}
+	return net.DefaultResolver
+}
+
+func isSRV(name string) bool {
+	labels := strings.SplitN(name, ".", 3)
+	return len(labels) == 3 && strings.HasPrefix(labels[0], "_") && strings.HasPrefix(labels[1], "_")
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestDNSEndpointSource(t *testing.T) {
+	resolver := fakeResolver(t, map[string][]net.IP{
+		"backend-1.test.": {net.IPv4(10, 0, 0, 1)},
+		"backend-2.test.": {net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3)},
+		"backend-3.test.": {net.IPv4(10, 0, 0, 4), net.ParseIP("fd00::4")},
+	}, map[string][]net.SRV{
+		"_http._tcp.backend.test.": {{Target: "backend-1.test.", Port: 8080}, {Target: "backend-2.test.", Port: 9090}},
+		"_http._tcp.broken.test.":  {{Target: "missing.test.", Port: 8080}},
+		"_http._tcp.failover.test.": {
+			{Target: "backend-1.test.", Port: 8080, Priority: 10, Weight: 3},
+			{Target: "backend-2.test.", Port: 8080, Priority: 10, Weight: 1},
+			{Target: "backend-3.test.", Port: 8080, Priority: 20},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		targets  []xds.DNSTarget
+		expected map[string][]resources.Endpoint
+		err      bool
+	}{
+		{
+			name:    "a records",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "backend-2.test", Port: 80}},
+			expected: map[string][]resources.Endpoint{
+				"backend": {{Host: "10.0.0.2", Port: 80}, {Host: "10.0.0.3", Port: 80}},
+			},
+		},
+		{
+			name:    "a and aaaa records",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "backend-3.test", Port: 80}},
+			expected: map[string][]resources.Endpoint{
+				"backend": {{Host: "10.0.0.4", Port: 80}, {Host: "fd00::4", Port: 80}},
+			},
+		},
+		{
+			name:    "srv priorities and weights",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "_http._tcp.failover.test"}},
+			expected: map[string][]resources.Endpoint{
+				"backend": {
+					{Host: "10.0.0.1", Port: 8080, Priority: 0, Weight: 3},
+					{Host: "10.0.0.2", Port: 8080, Priority: 0, Weight: 1},
+					{Host: "10.0.0.3", Port: 8080, Priority: 0, Weight: 1},
+					{Host: "10.0.0.4", Port: 8080, Priority: 1},
+					{Host: "fd00::4", Port: 8080, Priority: 1},
+				},
+			},
+		},
+		{
+			name:    "srv records",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "_http._tcp.backend.test"}},
+			expected: map[string][]resources.Endpoint{
+				"backend": {{Host: "10.0.0.1", Port: 8080}, {Host: "10.0.0.2", Port: 9090}, {Host: "10.0.0.3", Port: 9090}},
+			},
+		},
+		{
+			name: "targets of the same cluster are combined",
+			targets: []xds.DNSTarget{
+				{Cluster: "backend", Name: "backend-1.test", Port: 80},
+				{Cluster: "backend", Name: "backend-2.test", Port: 81},
+				{Cluster: "other", Name: "backend-1.test", Port: 82},
+			},
+			expected: map[string][]resources.Endpoint{
+				"backend": {{Host: "10.0.0.1", Port: 80}, {Host: "10.0.0.2", Port: 81}, {Host: "10.0.0.3", Port: 81}},
+				"other":   {{Host: "10.0.0.1", Port: 82}},
+			},
+		},
+		{
+			name:    "missing name",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "missing.test", Port: 80}},
+			err:     true,
+		},
+		{
+			name:    "missing srv target",
+			targets: []xds.DNSTarget{{Cluster: "backend", Name: "_http._tcp.broken.test"}},
+			err:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := xds.NewDNSEndpointSource(test.targets...)
+			source.Resolver = resolver
+			endpoints, err := source.Endpoints(context.Background())
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for cluster, expected := range test.expected {
+				require.ElementsMatch(t, expected, endpoints[cluster], cluster)
+			}
+			require.Len(t, endpoints, len(test.expected))
+		})
+	}
+}
+
+// fakeResolver returns a resolver querying a DNS server that answers the given IP addresses as A and
+// AAAA records, and the given SRV records. The other names do not exist.
+func fakeResolver(t *testing.T, a map[string][]net.IP, srv map[string][]net.SRV) *net.Resolver {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		b := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err = query.Unpack(b[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			question := query.Questions[0]
+			res := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+				Questions: query.Questions,
+			}
+			name := question.Name.String()
+			header := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60}
+			switch {
+			case question.Type == dnsmessage.TypeA && a[name] != nil:
+				for _, ip := range a[name] {
+					if ip.To4() == nil {
+						continue
+					}
+					body := &dnsmessage.AResource{}
+					copy(body.A[:], ip.To4())
+					res.Answers = append(res.Answers, dnsmessage.Resource{Header: header, Body: body})
+				}
+			case question.Type == dnsmessage.TypeAAAA && a[name] != nil:
+				for _, ip := range a[name] {
+					if ip.To4() != nil {
+						continue
+					}
+					body := &dnsmessage.AAAAResource{}
+					copy(body.AAAA[:], ip.To16())
+					res.Answers = append(res.Answers, dnsmessage.Resource{Header: header, Body: body})
+				}
+			case question.Type == dnsmessage.TypeSRV && srv[name] != nil:
+				for _, record := range srv[name] {
+					res.Answers = append(res.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.SRVResource{
+						Target:   dnsmessage.MustNewName(record.Target),
+						Port:     record.Port,
+						Priority: record.Priority,
+						Weight:   record.Weight,
+					}})
+				}
+			default:
+				res.RCode = dnsmessage.RCodeNameError
+			}
+			packed, err := res.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
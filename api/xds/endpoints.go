@@ -0,0 +1,160 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	"github.com/dio/rundown/api/xds/resources"
+	"github.com/dio/rundown/internal/xds"
+)
+
+// EndpointSource provides the endpoints of clusters, e.g. from a service registry. The endpoints are
+// served as the load assignments of the clusters, hence the cluster definitions can stay the same
+// while the membership changes.
+type EndpointSource interface {
+	// Endpoints returns the current endpoints, keyed by cluster names.
+	Endpoints(ctx context.Context) (map[string][]resources.Endpoint, error)
+	// Watch blocks until the context is done, and calls onChange every time the endpoints are
+	// possibly changed. Errors that are not fatal for watching are reported through onError.
+	Watch(ctx context.Context, onChange func(), onError func(error))
+}
+
+// endpointSource is an endpoint source added to the service.
+type endpointSource struct {
+	EndpointSource
+	nodeGroup string
+	endpoints map[string][]resources.Endpoint // the last known endpoints.
+}
+
+// AddEndpointSource adds a source of the endpoints served to the members of a node group. The load
+// assignments built from the source replace the configured ones of the same cluster names. This
+// needs to be called before the service runs.
+func (s *Service) AddEndpointSource(nodeGroup string, source EndpointSource) {
+	s.sources = append(s.sources, &endpointSource{EndpointSource: source, nodeGroup: nodeGroup})
+}
+
+// fetchEndpoints gets the current endpoints from all of the sources.
+func (s *Service) fetchEndpoints(ctx context.Context) error {
+	for i, source := range s.sources {
+		endpoints, err := source.Endpoints(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get endpoints from source at index %d: %w", i, err)
+		}
+		s.mu.Lock()
+		source.endpoints = normalizeEndpoints(endpoints)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// watchEndpoints adds a watch for each source, which pushes new snapshot versions when the endpoints
+// of the source change. When the source fails, its last known endpoints are kept.
+func (s *Service) watchEndpoints() {
+	for i := range s.sources {
+		source := s.sources[i]
+		s.watches = append(s.watches, func(ctx context.Context) {
+			onError := func(err error) {
+				s.cfg.Logger.Error("failed to get endpoints, keep serving the last known endpoints", err,
+					"node_group", source.nodeGroup)
+			}
+			source.Watch(ctx, func() {
+				endpoints, err := source.Endpoints(ctx)
+				if err != nil {
+					onError(err)
+					return
+				}
+				if !s.setEndpoints(source, normalizeEndpoints(endpoints)) {
+					return
+				}
+				if err = s.setSnapshots(ctx, nil); err != nil {
+					s.cfg.Logger.Error("failed to update endpoints", err, "node_group", source.nodeGroup)
+					return
+				}
+				s.cfg.Logger.Info("endpoints updated", "node_group", source.nodeGroup, "version", s.currentVersion())
+			}, onError)
+		})
+	}
+}
+
+// setEndpoints sets the endpoints of the source, and returns true when they are changed.
+func (s *Service) setEndpoints(source *endpointSource, endpoints map[string][]resources.Endpoint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reflect.DeepEqual(source.endpoints, endpoints) {
+		return false
+	}
+	source.endpoints = endpoints
+	return true
+}
+
+// endpointResources returns the load assignments built from the last known endpoints of the
+// sources, keyed by node group names. This is called with s.mu held.
+func (s *Service) endpointResources() map[string]map[resource.Type][]types.Resource {
+	extra := make(map[string]map[resource.Type][]types.Resource)
+	for _, source := range s.sources {
+		if _, ok := extra[source.nodeGroup]; !ok {
+			extra[source.nodeGroup] = make(map[resource.Type][]types.Resource)
+		}
+		for clusterName, endpoints := range source.endpoints {
+			extra[source.nodeGroup][resource.EndpointType] = append(extra[source.nodeGroup][resource.EndpointType],
+				resources.LoadAssignment(clusterName, endpoints...))
+		}
+	}
+	return extra
+}
+
+// extraResources returns the resources added to the ones built from the config, keyed by node group
+// names: the load assignments from the endpoint sources, replaced by the resources set through
+// SetResources of the same type and name. This is called with s.mu held.
+func (s *Service) extraResources() map[string]map[resource.Type][]types.Resource {
+	extra := s.endpointResources()
+	for group, resources := range s.resources {
+		if _, ok := extra[group]; !ok {
+			extra[group] = make(map[resource.Type][]types.Resource)
+		}
+		xds.ReplaceResources(extra[group], resources)
+	}
+	return extra
+}
+
+// normalizeEndpoints sorts the endpoints, so the same membership is always equal.
+func normalizeEndpoints(endpoints map[string][]resources.Endpoint) map[string][]resources.Endpoint {
+	normalized := make(map[string][]resources.Endpoint, len(endpoints))
+	for clusterName, list := range endpoints {
+		sorted := append([]resources.Endpoint(nil), list...)
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Host != sorted[j].Host {
+				return sorted[i].Host < sorted[j].Host
+			}
+			if sorted[i].Port != sorted[j].Port {
+				return sorted[i].Port < sorted[j].Port
+			}
+			if sorted[i].Priority != sorted[j].Priority {
+				return sorted[i].Priority < sorted[j].Priority
+			}
+			return sorted[i].Weight < sorted[j].Weight
+		})
+		normalized[clusterName] = sorted
+	}
+	return normalized
+}
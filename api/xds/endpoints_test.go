@@ -0,0 +1,225 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// endpointsConfig has the EDS clusters backend and static, with their configured load assignments, for
+// the default node group and the edge node group.
+const endpointsConfig = `
+resources: &resources
+  clusters:
+    - name: backend
+      type: EDS
+      eds_cluster_config: {eds_config: {ads: {}, resource_api_version: V3}}
+    - name: static
+      type: EDS
+      eds_cluster_config: {eds_config: {ads: {}, resource_api_version: V3}}
+  endpoints:
+    - cluster_name: backend
+      endpoints: [{lb_endpoints: [{endpoint: {address: {socket_address: {address: 10.0.0.1, port_value: 80}}}}]}]
+    - cluster_name: static
+      endpoints: [{lb_endpoints: [{endpoint: {address: {socket_address: {address: 10.0.1.1, port_value: 80}}}}]}]
+node_groups:
+  - name: edge
+    match: {clusters: [edge]}
+    resources: *resources
+`
+
+func TestEndpointSources(t *testing.T) {
+	configured := map[string][]string{"backend": {"10.0.0.1:80"}, "static": {"10.0.1.1:80"}}
+	tests := []struct {
+		name    string
+		sources map[string][]*fakeSource // keyed by node group names.
+		// The expected load assignments, keyed by node group names, then by cluster names.
+		expected map[string]map[string][]string
+	}{
+		{
+			name: "no source",
+			expected: map[string]map[string][]string{
+				xds.DefaultNodeGroup: configured,
+				"edge":               configured,
+			},
+		},
+		{
+			name: "replace the configured load assignment",
+			sources: map[string][]*fakeSource{
+				xds.DefaultNodeGroup: {newFakeSource(map[string][]resources.Endpoint{
+					"backend": {{Host: "10.0.0.3", Port: 8080}, {Host: "10.0.0.2", Port: 8080}},
+				})},
+			},
+			expected: map[string]map[string][]string{
+				xds.DefaultNodeGroup: {"backend": {"10.0.0.2:8080", "10.0.0.3:8080"}, "static": {"10.0.1.1:80"}},
+				"edge":               configured,
+			},
+		},
+		{
+			name: "sources of node groups",
+			sources: map[string][]*fakeSource{
+				xds.DefaultNodeGroup: {
+					newFakeSource(map[string][]resources.Endpoint{"backend": {{Host: "10.0.0.2", Port: 80}}}),
+					newFakeSource(map[string][]resources.Endpoint{"static": {{Host: "10.0.1.2", Port: 80}}}),
+				},
+				"edge": {newFakeSource(map[string][]resources.Endpoint{"static": {}})},
+			},
+			expected: map[string]map[string][]string{
+				xds.DefaultNodeGroup: {"backend": {"10.0.0.2:80"}, "static": {"10.0.1.2:80"}},
+				"edge":               {"backend": {"10.0.0.1:80"}, "static": {}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, _ := startService(t, loadConfig(t, endpointsConfig), func(s *xds.Service) {
+				for group, sources := range test.sources {
+					for _, source := range sources {
+						s.AddEndpointSource(group, source)
+					}
+				}
+			})
+			for group, expected := range test.expected {
+				require.Equal(t, expected, loadAssignments(t, s, group), group)
+			}
+		})
+	}
+}
+
+func TestEndpointSourceChanges(t *testing.T) {
+	source := newFakeSource(map[string][]resources.Endpoint{"backend": {{Host: "10.0.0.2", Port: 80}}})
+	s, _ := startService(t, loadConfig(t, endpointsConfig), func(s *xds.Service) {
+		s.AddEndpointSource(xds.DefaultNodeGroup, source)
+	})
+	snapshot, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	version := snapshot.GetVersion(resource.EndpointType)
+
+	// The same membership in another order is not a change.
+	source.set(map[string][]resources.Endpoint{"backend": {{Host: "10.0.0.2", Port: 80}}}, nil)
+	// A failing source keeps its last known endpoints.
+	source.set(nil, errors.New("unavailable"))
+	time.Sleep(50 * time.Millisecond)
+	snapshot, err = s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, version, snapshot.GetVersion(resource.EndpointType))
+
+	// Endpoints of an unknown cluster make the snapshot inconsistent, hence they are not served.
+	source.set(map[string][]resources.Endpoint{"unknown": {{Host: "10.0.0.2", Port: 80}}}, nil)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, map[string][]string{"backend": {"10.0.0.2:80"}, "static": {"10.0.1.1:80"}},
+		loadAssignments(t, s, xds.DefaultNodeGroup))
+
+	source.set(map[string][]resources.Endpoint{"backend": {{Host: "10.0.0.3", Port: 80}, {Host: "10.0.0.4", Port: 80}}}, nil)
+	require.Eventually(t, func() bool {
+		return len(loadAssignments(t, s, xds.DefaultNodeGroup)["backend"]) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestEndpointSourceValidate(t *testing.T) {
+	s := xds.New(nil, &xds.Config{Config: loadConfig(t, endpointsConfig)})
+	s.AddEndpointSource("unknown", newFakeSource(nil))
+	require.Error(t, s.Validate())
+
+	s = xds.New(nil, &xds.Config{Config: loadConfig(t, endpointsConfig)})
+	failing := newFakeSource(nil)
+	failing.set(nil, errors.New("unavailable"))
+	s.AddEndpointSource(xds.DefaultNodeGroup, failing)
+	require.Error(t, s.Validate())
+}
+
+// fakeSource is an endpoint source with the endpoints set by the test.
+type fakeSource struct {
+	mu        sync.Mutex
+	endpoints map[string][]resources.Endpoint
+	err       error
+	changed   chan struct{}
+}
+
+func newFakeSource(endpoints map[string][]resources.Endpoint) *fakeSource {
+	return &fakeSource{endpoints: endpoints, changed: make(chan struct{}, 1)}
+}
+
+func (f *fakeSource) Endpoints(context.Context) (map[string][]resources.Endpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.endpoints, f.err
+}
+
+func (f *fakeSource) Watch(ctx context.Context, onChange func(), _ func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.changed:
+			onChange()
+		}
+	}
+}
+
+// set sets the endpoints, or the error of getting them, and notifies the change.
+func (f *fakeSource) set(endpoints map[string][]resources.Endpoint, err error) {
+	f.mu.Lock()
+	if err == nil {
+		f.endpoints = endpoints
+	}
+	f.err = err
+	f.mu.Unlock()
+	f.changed <- struct{}{}
+}
+
+func loadConfig(t *testing.T, config string) *configv1.Config {
+	b, err := yaml.YAMLToJSON([]byte(config))
+	require.NoError(t, err)
+	var cfg configv1.Config
+	require.NoError(t, protojson.Unmarshal(b, &cfg))
+	return &cfg
+}
+
+// loadAssignments returns the endpoint addresses (host:port) of the load assignments served to the
+// node group, keyed by cluster names.
+func loadAssignments(t *testing.T, s *xds.Service, nodeGroup string) map[string][]string {
+	snapshot, err := s.Snapshot(nodeGroup)
+	require.NoError(t, err)
+	assignments := make(map[string][]string)
+	for name, r := range snapshot.GetResources(resource.EndpointType) {
+		addresses := []string{}
+		for _, locality := range r.(*endpointv3.ClusterLoadAssignment).Endpoints {
+			for _, lb := range locality.LbEndpoints {
+				address := lb.GetEndpoint().GetAddress().GetSocketAddress()
+				addresses = append(addresses, fmt.Sprintf("%s:%d", address.GetAddress(), address.GetPortValue()))
+			}
+		}
+		sort.Strings(addresses)
+		assignments[name] = addresses
+	}
+	return assignments
+}
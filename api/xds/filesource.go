@@ -0,0 +1,76 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dio/rundown/api/xds/resources"
+	"github.com/dio/rundown/internal/watcher"
+)
+
+// FileEndpointSource is an endpoint source that reads the endpoints from a JSON or YAML file, keyed
+// by cluster names, e.g.:
+//
+//	backend:
+//	  - host: 10.0.0.1
+//	    port: 8080
+//	  - host: 10.0.0.2
+//	    port: 8080
+//
+// The file is watched for changes.
+type FileEndpointSource struct {
+	path string
+}
+
+var _ EndpointSource = (*FileEndpointSource)(nil)
+
+// NewFileEndpointSource returns a new FileEndpointSource that reads the given file.
+func NewFileEndpointSource(path string) *FileEndpointSource {
+	return &FileEndpointSource{path: path}
+}
+
+// Endpoints reads the endpoints from the file.
+func (f *FileEndpointSource) Endpoints(context.Context) (map[string][]resources.Endpoint, error) {
+	b, err := os.ReadFile(f.path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	if ext := filepath.Ext(f.path); ext == ".yaml" || ext == ".yml" {
+		if b, err = yaml.YAMLToJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	var endpoints map[string][]resources.Endpoint
+	if err = json.Unmarshal(b, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// Watch watches the file for changes.
+func (f *FileEndpointSource) Watch(ctx context.Context, onChange func(), onError func(error)) {
+	w, err := watcher.New(f.path)
+	if err != nil {
+		onError(err)
+		return
+	}
+	w.Run(ctx, onChange, onError)
+}
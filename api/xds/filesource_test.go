@@ -0,0 +1,109 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestFileEndpointSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected map[string][]resources.Endpoint
+	}{
+		{
+			name: "yaml",
+			file: "endpoints.yaml",
+			content: `backend:
+  - host: 10.0.0.1
+    port: 8080
+  - host: 10.0.0.2
+    port: 8080
+other: []
+`,
+			expected: map[string][]resources.Endpoint{
+				"backend": {{Host: "10.0.0.1", Port: 8080}, {Host: "10.0.0.2", Port: 8080}},
+				"other":   {},
+			},
+		},
+		{
+			name:     "json",
+			file:     "endpoints.json",
+			content:  `{"backend": [{"host": "10.0.0.1", "port": 8080}]}`,
+			expected: map[string][]resources.Endpoint{"backend": {{Host: "10.0.0.1", Port: 8080}}},
+		},
+		{name: "invalid yaml", file: "endpoints.yml", content: "backend: [\n"},
+		{name: "invalid endpoints", file: "endpoints.json", content: `{"backend": {"host": "10.0.0.1"}}`},
+		{name: "missing file", file: "endpoints.json"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), test.file)
+			if test.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(test.content), 0o600))
+			}
+			endpoints, err := xds.NewFileEndpointSource(path).Endpoints(context.Background())
+			if test.expected == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, endpoints)
+		})
+	}
+}
+
+func TestFileEndpointSourceWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		xds.NewFileEndpointSource(path).Watch(ctx, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}, func(err error) {
+			t.Errorf("failed to watch: %v", err)
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		require.NoError(t, os.WriteFile(path, []byte(`{"backend": []}`), 0o600))
+		select {
+		case <-changed:
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-watched
+}
@@ -19,8 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
 
 	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
 	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -29,16 +32,22 @@ import (
 	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
 	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
 	secretservice "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cache "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
 	"github.com/tetratelabs/run"
 	"github.com/tetratelabs/telemetry"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/encoding/protojson"
 	"sigs.k8s.io/yaml"
 
 	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/certs"
+	"github.com/dio/rundown/internal/listener"
 	"github.com/dio/rundown/internal/managed"
+	"github.com/dio/rundown/internal/watcher"
 	"github.com/dio/rundown/internal/xds"
 )
 
@@ -54,9 +63,15 @@ func New(g *run.Group, cfg *Config) *Service {
 	if cfg == nil {
 		cfg = &Config{} // TODO(dio): Have a way to generate default config.
 	}
+	if cfg.Logger == nil {
+		cfg.Logger = telemetry.NoopLogger()
+	}
 	return &Service{
-		cfg: cfg,
-		g:   g,
+		cfg:       cfg,
+		g:         g,
+		groups:    make(map[string]struct{}),
+		runtime:   make(xds.RuntimeOverrides),
+		resources: make(map[string]map[resource.Type][]types.Resource),
 		managed: &managed.Flags{
 			Titleize: func(string) string {
 				return "xDS Service"
@@ -74,6 +89,25 @@ type Service struct {
 	listener   net.Listener
 	grpcServer *grpc.Server
 	cache      cache.SnapshotCache
+	nodeGroups *xds.NodeGroups
+	callbacks  *callbacks
+
+	adminServer   *http.Server
+	adminListener net.Listener
+
+	// watches are run in the background while serving, until the service is stopped.
+	watches     []func(context.Context)
+	watchCtx    context.Context
+	stopWatches context.CancelFunc
+
+	mu      sync.Mutex
+	version uint64
+	groups  map[string]struct{} // names of node groups that have snapshots.
+	applied *configv1.Config    // the config of the current snapshots.
+	runtime xds.RuntimeOverrides
+	sources []*endpointSource
+	// resources are set through SetResources, keyed by node group names.
+	resources map[string]map[resource.Type][]types.Resource
 }
 
 var _ run.Config = (*Service)(nil)
@@ -97,30 +131,43 @@ func (s *Service) Validate() error {
 	}
 
 	if s.managed.ConfigFile != "" {
-		b, err := os.ReadFile(s.managed.ConfigFile)
+		cfg, err := loadConfig(s.managed.ConfigFile)
 		if err != nil {
 			return err
 		}
-
-		// Probably a .yaml file. We simply check the extension here.
-		if filepath.Ext(s.managed.ConfigFile) == ".yaml" || filepath.Ext(s.managed.ConfigFile) == ".yml" {
-			b, err = yaml.YAMLToJSON(b)
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-		}
-
-		var cfg configv1.Config
-		if err = protojson.Unmarshal(b, &cfg); err != nil {
-			return err
-		}
-		s.cfg.Config = &cfg
+		s.cfg.Config = cfg
 	}
 
 	if s.cfg.Config == nil {
 		return errors.New("xds service config is required")
 	}
-	return s.cfg.Config.ValidateAll()
+	if err := s.cfg.Config.ValidateAll(); err != nil {
+		return err
+	}
+	if _, err := listener.ParseSocketMode(s.cfg.Config.SocketMode); err != nil {
+		return err
+	}
+	if err := xds.ValidateGrpcServer(s.cfg.Config.GrpcServer); err != nil {
+		return fmt.Errorf("invalid grpc server settings: %w", err)
+	}
+	for _, service := range s.cfg.Config.Services {
+		if _, ok := registerFuncs[service]; !ok {
+			return fmt.Errorf("invalid discovery service: %s", service)
+		}
+	}
+	for _, source := range s.sources {
+		if !hasNodeGroup(s.cfg.Config, source.nodeGroup) {
+			return fmt.Errorf("unknown node group %q of endpoint source", source.nodeGroup)
+		}
+	}
+	if err := s.fetchEndpoints(context.Background()); err != nil {
+		return err
+	}
+	// Make sure the declared node groups and resources are valid and consistent.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := xds.NewSnapshotsWith("", s.cfg.Config, s.endpointResources())
+	return err
 }
 
 // PreRun prepares the service to run.
@@ -131,83 +178,311 @@ func (s *Service) PreRun() (err error) {
 
 	ctx := context.Background()
 
-	// TODO(dio): Change this implementation.
-	s.cache = cache.NewSnapshotCache(false, cache.IDHash{}, logger{})
-	snapshot := xds.GenerateSnapshot()
-	if err := snapshot.Consistent(); err != nil {
+	// The snapshots are keyed by node group names.
+	if s.nodeGroups, err = xds.NewNodeGroups(s.cfg.Config.NodeGroups); err != nil {
 		return err
 	}
-
-	if err := s.cache.SetSnapshot(ctx, "test-id", snapshot); err != nil {
+	s.mu.Lock()
+	s.cache = cache.NewSnapshotCache(s.cfg.Config.Ads, s.nodeGroups, logger{s.cfg.Logger})
+	s.mu.Unlock()
+	if err = s.setSnapshots(ctx, s.cfg.Config); err != nil {
 		return err
 	}
 
-	s.server = server.NewServer(ctx, s.cache, &callbacks{})
+	// The watches are stopped on GracefulStop, which may be called before Serve.
+	s.watchCtx, s.stopWatches = context.WithCancel(context.Background())
+
+	// When the config is loaded from a file, we watch it for changes.
+	if s.managed.ConfigFile != "" {
+		if err = s.watch(s.reload, s.managed.ConfigFile); err != nil {
+			return err
+		}
+	}
+	// The snapshots are rebuilt when the resource files or the secret files change.
+	if paths := xds.WatchedPaths(s.cfg.Config); len(paths) > 0 {
+		if err = s.watch(s.refresh, paths...); err != nil {
+			return err
+		}
+	}
+	// The snapshots are rebuilt when the endpoints from the sources change.
+	s.watchEndpoints()
+
+	s.callbacks = newCallbacks(s.cfg.Logger, s.nodeGroups, s.cfg.Config.Delta)
+	s.server = server.NewServer(ctx, s.cache, s.callbacks)
 
 	// TODO(dio): Take this gRPC server setup to a dedicated module.
-	s.grpcServer = grpc.NewServer()
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.cfg.Config.Host, s.cfg.Config.Port))
+	opts := xds.GrpcServerOptions(s.cfg.Config.GrpcServer)
+	if t := s.cfg.Config.Tls; t != nil {
+		reloader, err := certs.NewReloader(t.CertFile, t.KeyFile, t.CaFile, t.RequireClientCert)
+		if err != nil {
+			return err
+		}
+		// Rotated certificates are used for the subsequent handshakes.
+		if err = s.watch(func() {
+			if err := reloader.Reload(); err != nil {
+				s.cfg.Logger.Error("failed to reload xds service certificates", err)
+				return
+			}
+			s.cfg.Logger.Info("xds service certificates reloaded")
+		}, reloader.Paths()...); err != nil {
+			return err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	}
+	s.grpcServer = grpc.NewServer(opts...)
+	mode, err := listener.ParseSocketMode(s.cfg.Config.SocketMode)
 	if err != nil {
 		return err
 	}
-	s.listener = listener
+	if s.listener, err = listener.Listen(s.address(), mode); err != nil {
+		return err
+	}
 
 	// This probably requires refactoring. It depends on how we do tests.
 	s.registerServiceServers()
+
+	if admin := s.cfg.Config.Admin; admin != nil {
+		s.adminServer = &http.Server{Handler: s.adminHandler()} //nolint:gosec
+		if s.adminListener, err = listener.Listen(listener.Address(admin.Host, admin.Port), mode); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Serve runs the service.
 func (s *Service) Serve() (err error) {
+	for _, watch := range s.watches {
+		go watch(s.watchCtx)
+	}
+	if s.adminServer != nil {
+		go func() {
+			if err := s.adminServer.Serve(s.adminListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.cfg.Logger.Error("failed to serve xds admin", err)
+			}
+		}()
+	}
 	return s.grpcServer.Serve(s.listener)
 }
 
 // GracefulStop stops the underlying process by sending interrupt.
 func (s *Service) GracefulStop() {
+	if s.stopWatches != nil {
+		s.stopWatches()
+	}
+	if s.adminServer != nil {
+		_ = s.adminServer.Shutdown(context.Background())
+	}
 	s.grpcServer.GracefulStop()
+	if err := listener.Cleanup(s.address()); err != nil {
+		s.cfg.Logger.Error("failed to remove xds service socket file", err)
+	}
 }
 
-func (s *Service) registerServiceServers() {
-	// Register services.
-	discoveryservice.RegisterAggregatedDiscoveryServiceServer(s.grpcServer, s.server)
-	endpointservice.RegisterEndpointDiscoveryServiceServer(s.grpcServer, s.server)
-	clusterservice.RegisterClusterDiscoveryServiceServer(s.grpcServer, s.server)
-	routeservice.RegisterRouteDiscoveryServiceServer(s.grpcServer, s.server)
-	listenerservice.RegisterListenerDiscoveryServiceServer(s.grpcServer, s.server)
-	secretservice.RegisterSecretDiscoveryServiceServer(s.grpcServer, s.server)
-	runtimeservice.RegisterRuntimeDiscoveryServiceServer(s.grpcServer, s.server)
+// address returns the address of the xDS gRPC server.
+func (s *Service) address() string {
+	return listener.Address(s.cfg.Config.Host, s.cfg.Config.Port)
 }
 
-type logger struct{}
+// watch registers a watch on the given paths, to be run while serving. The onChange function is
+// called every time the content of the paths changes.
+func (s *Service) watch(onChange func(), paths ...string) error {
+	w, err := watcher.New(paths...)
+	if err != nil {
+		return err
+	}
+	s.watches = append(s.watches, func(ctx context.Context) {
+		w.Run(ctx, onChange, func(err error) {
+			s.cfg.Logger.Error("failed to watch files", err, "paths", paths)
+		})
+	})
+	return nil
+}
 
-func (l logger) Debugf(format string, args ...interface{}) {}
-func (l logger) Infof(format string, args ...interface{})  {}
-func (l logger) Warnf(format string, args ...interface{})  {}
-func (l logger) Errorf(format string, args ...interface{}) {}
+// reload reloads the config file and pushes new snapshot versions built from it. Only the node groups
+// and resources are reloaded, changing the server address or the watched resource directories and
+// secret files requires a restart. When the reloaded config is invalid, the last good snapshots are
+// kept.
+func (s *Service) reload() {
+	cfg, err := loadConfig(s.managed.ConfigFile)
+	if err == nil {
+		err = cfg.ValidateAll()
+	}
+	if err == nil {
+		err = s.setSnapshots(context.Background(), cfg)
+	}
+	if err != nil {
+		s.cfg.Logger.Error("failed to reload xds service config, keep serving the last good snapshots", err,
+			"config", s.managed.ConfigFile)
+		return
+	}
+	s.cfg.Logger.Info("xds service config reloaded", "config", s.managed.ConfigFile, "version", s.currentVersion())
+}
 
-type callbacks struct{}
+// refresh rebuilds the snapshots from the last applied config, to pick up the changes of the
+// resource files and the secret files. When the files are invalid, the last good snapshots are kept.
+func (s *Service) refresh() {
+	if err := s.setSnapshots(context.Background(), nil); err != nil {
+		s.cfg.Logger.Error("failed to reload xds resource files, keep serving the last good snapshots", err)
+		return
+	}
+	s.cfg.Logger.Info("xds resource files reloaded", "version", s.currentVersion())
+}
 
-func (c *callbacks) Report() {}
-func (c *callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error {
-	return nil
+// setSnapshots builds a snapshot for each node group from the given config with a bumped version,
+// and sets them to the cache. A nil config means the last applied config. The runtime overrides are
+// merged into the config, then the load assignments from the endpoint sources and the resources set
+// through SetResources are added. The snapshots of the node groups that no longer exist are cleared.
+func (s *Service) setSnapshots(ctx context.Context, cfg *configv1.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.setSnapshotsLocked(ctx, cfg)
 }
-func (c *callbacks) OnStreamClosed(id int64) {}
-func (c *callbacks) OnDeltaStreamOpen(_ context.Context, id int64, typ string) error {
+
+// setSnapshotsLocked is setSnapshots, it must be called with s.mu held.
+func (s *Service) setSnapshotsLocked(ctx context.Context, cfg *configv1.Config) error {
+	if cfg == nil {
+		cfg = s.applied
+	}
+	snapshots, err := xds.NewSnapshotsWith(strconv.FormatUint(s.version+1, 10), s.runtime.Apply(cfg),
+		s.extraResources())
+	if err != nil {
+		return err
+	}
+	// The snapshots are set before the node groups are updated, so a node is never hashed to a node
+	// group without a snapshot. When setting one of them fails, the previous snapshots are restored.
+	previous := make(map[string]cache.ResourceSnapshot, len(snapshots))
+	for group, snapshot := range snapshots {
+		if prev, err := s.cache.GetSnapshot(group); err == nil {
+			previous[group] = prev
+		}
+		if err = s.cache.SetSnapshot(ctx, group, snapshot); err != nil {
+			s.restoreSnapshots(ctx, previous, snapshots)
+			return err
+		}
+	}
+	if err = s.nodeGroups.Update(cfg.NodeGroups); err != nil {
+		s.restoreSnapshots(ctx, previous, snapshots)
+		return err
+	}
+	for group := range s.groups {
+		if _, ok := snapshots[group]; !ok {
+			s.cache.ClearSnapshot(group)
+			delete(s.groups, group)
+		}
+	}
+	for group := range snapshots {
+		s.groups[group] = struct{}{}
+	}
+	s.version++
+	s.applied = cfg
 	return nil
 }
-func (c *callbacks) OnDeltaStreamClosed(id int64) {}
-func (c *callbacks) OnStreamRequest(int64, *discoveryservice.DiscoveryRequest) error {
-	return nil
+
+// restoreSnapshots sets back the previous snapshots of the given node groups, or clears the snapshots
+// of the node groups that had none. This must be called with s.mu held.
+func (s *Service) restoreSnapshots(ctx context.Context, previous map[string]cache.ResourceSnapshot,
+	groups map[string]*cache.Snapshot) {
+	for group := range groups {
+		prev, ok := previous[group]
+		if !ok {
+			s.cache.ClearSnapshot(group)
+			continue
+		}
+		if err := s.cache.SetSnapshot(ctx, group, prev); err != nil {
+			s.cfg.Logger.Error("failed to restore snapshot", err, "node_group", group)
+		}
+	}
 }
-func (c *callbacks) OnStreamResponse(context.Context, int64, *discoveryservice.DiscoveryRequest, *discoveryservice.DiscoveryResponse) {
+
+// currentVersion returns the version of the last snapshot set to the cache.
+func (s *Service) currentVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strconv.FormatUint(s.version, 10)
 }
-func (c *callbacks) OnStreamDeltaResponse(id int64, req *discoveryservice.DeltaDiscoveryRequest, res *discoveryservice.DeltaDiscoveryResponse) {
+
+// hasNodeGroup returns true when the name is DefaultNodeGroup or one of the configured node groups.
+func hasNodeGroup(cfg *configv1.Config, name string) bool {
+	if name == xds.DefaultNodeGroup {
+		return true
+	}
+	for _, group := range cfg.NodeGroups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
 }
-func (c *callbacks) OnStreamDeltaRequest(id int64, req *discoveryservice.DeltaDiscoveryRequest) error {
-	return nil
+
+// loadConfig loads the config from a JSON or YAML file.
+func loadConfig(path string) (*configv1.Config, error) {
+	b, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	// Probably a .yaml file. We simply check the extension here.
+	if filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml" {
+		b, err = yaml.YAMLToJSON(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	var cfg configv1.Config
+	if err = protojson.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
 }
-func (c *callbacks) OnFetchRequest(_ context.Context, req *discoveryservice.DiscoveryRequest) error {
-	return nil
+
+// registerFuncs maps a discovery service to its registration function.
+var registerFuncs = map[configv1.DiscoveryService]func(*grpc.Server, server.Server){
+	configv1.DiscoveryService_DISCOVERY_SERVICE_ADS: func(g *grpc.Server, s server.Server) {
+		discoveryservice.RegisterAggregatedDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_EDS: func(g *grpc.Server, s server.Server) {
+		endpointservice.RegisterEndpointDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_CDS: func(g *grpc.Server, s server.Server) {
+		clusterservice.RegisterClusterDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_RDS: func(g *grpc.Server, s server.Server) {
+		routeservice.RegisterRouteDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_LDS: func(g *grpc.Server, s server.Server) {
+		listenerservice.RegisterListenerDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_SDS: func(g *grpc.Server, s server.Server) {
+		secretservice.RegisterSecretDiscoveryServiceServer(g, s)
+	},
+	configv1.DiscoveryService_DISCOVERY_SERVICE_RTDS: func(g *grpc.Server, s server.Server) {
+		runtimeservice.RegisterRuntimeDiscoveryServiceServer(g, s)
+	},
 }
-func (c *callbacks) OnFetchResponse(*discoveryservice.DiscoveryRequest, *discoveryservice.DiscoveryResponse) {
+
+func (s *Service) registerServiceServers() {
+	services := s.cfg.Config.Services
+	if len(services) == 0 {
+		// Register all services.
+		services = []configv1.DiscoveryService{
+			configv1.DiscoveryService_DISCOVERY_SERVICE_ADS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_EDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_CDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_RDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_LDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_SDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_RTDS,
+		}
+	}
+
+	registered := make(map[configv1.DiscoveryService]struct{}, len(services))
+	for _, service := range services {
+		if _, ok := registered[service]; ok {
+			continue
+		}
+		registerFuncs[service](s.grpcServer, s.server)
+		registered[service] = struct{}{}
+	}
 }
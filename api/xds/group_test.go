@@ -0,0 +1,410 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/dio/rundown/api/xds"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "xds.sock")
+	path := filepath.Join(dir, "xds.yaml")
+	cfg := &configv1.Config{Host: "unix://" + socket, Resources: runtimeResources(t, "version", "v1")}
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	s, _ := startServiceWith(t, &xds.Config{Config: cfg, Logger: logger}, withConfigFile(t, cfg, path))
+
+	conn := dial(t, socket)
+	res := fetch(t, conn, resource.RuntimeType, "rtds")
+	require.Equal(t, "v1", runtimeLayer(t, res, "version"))
+
+	// The new resources are served with a bumped version.
+	cfg.Resources = runtimeResources(t, "version", "v2")
+	writeConfigFile(t, cfg, path)
+	require.Eventually(t, func() bool {
+		return logger.count("xds service config reloaded") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	reloaded := fetch(t, conn, resource.RuntimeType, "rtds")
+	require.Equal(t, "v2", runtimeLayer(t, reloaded, "version"))
+	require.NotEqual(t, res.VersionInfo, reloaded.VersionInfo)
+
+	// An invalid config keeps the last good snapshot.
+	require.NoError(t, os.WriteFile(path, []byte("resources: ["), 0o600))
+	require.Eventually(t, func() bool {
+		return logger.count("failed to reload xds service config, keep serving the last good snapshots") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	kept := fetch(t, conn, resource.RuntimeType, "rtds")
+	require.Equal(t, "v2", runtimeLayer(t, kept, "version"))
+	require.Equal(t, reloaded.VersionInfo, kept.VersionInfo)
+	snapshot, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, reloaded.VersionInfo, snapshot.GetVersion(resource.RuntimeType))
+}
+
+func TestResourcesDirReload(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "xds.sock")
+	resources := filepath.Join(dir, "resources")
+	require.NoError(t, os.Mkdir(resources, 0o750))
+	path := filepath.Join(resources, "runtime.yaml")
+	writeRuntimeFile(t, path, "v1")
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	startServiceWith(t, &xds.Config{Config: &configv1.Config{Host: "unix://" + socket, ResourcesDir: resources}, Logger: logger})
+
+	conn := dial(t, socket)
+	res := fetch(t, conn, resource.RuntimeType, "rtds")
+	require.Equal(t, "v1", runtimeLayer(t, res, "version"))
+
+	// Changing a file in the directory rebuilds the snapshot, served with a bumped version.
+	writeRuntimeFile(t, path, "v2")
+	require.Eventually(t, func() bool {
+		return logger.count("xds resource files reloaded") > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	var reloaded *discoveryservice.DiscoveryResponse
+	require.Eventually(t, func() bool {
+		reloaded = fetch(t, conn, resource.RuntimeType, "rtds")
+		return runtimeLayer(t, reloaded, "version") == "v2"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotEqual(t, res.VersionInfo, reloaded.VersionInfo)
+
+	// An invalid file keeps the last good snapshot.
+	require.NoError(t, os.WriteFile(path, []byte("layer: ["), 0o600))
+	require.Eventually(t, func() bool {
+		return logger.count("failed to reload xds resource files, keep serving the last good snapshots") > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	kept := fetch(t, conn, resource.RuntimeType, "rtds")
+	require.Equal(t, "v2", runtimeLayer(t, kept, "version"))
+	require.Equal(t, reloaded.VersionInfo, kept.VersionInfo)
+}
+
+func TestDelta(t *testing.T) {
+	for _, delta := range []bool{false, true} {
+		t.Run(fmt.Sprintf("delta %t", delta), func(t *testing.T) {
+			socket := filepath.Join(t.TempDir(), "xds.sock")
+			startService(t, &configv1.Config{
+				Host:      "unix://" + socket,
+				Delta:     delta,
+				Resources: runtimeResources(t, "version", "v1"),
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			client := discoveryservice.NewAggregatedDiscoveryServiceClient(dial(t, socket))
+			stream, err := client.DeltaAggregatedResources(ctx)
+			require.NoError(t, err)
+			require.NoError(t, stream.Send(&discoveryservice.DeltaDiscoveryRequest{
+				Node:                   &core.Node{Id: "test"},
+				TypeUrl:                resource.RuntimeType,
+				ResourceNamesSubscribe: []string{"rtds"},
+			}))
+			res, err := stream.Recv()
+			if !delta {
+				require.Equal(t, codes.Unimplemented, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, res.Resources, 1)
+			require.Equal(t, "rtds", res.Resources[0].Name)
+
+			// The state of the world streams are still served.
+			require.Equal(t, "v1", runtimeLayer(t, fetch(t, dial(t, socket), resource.RuntimeType, "rtds"), "version"))
+		})
+	}
+}
+
+func TestDiscoveryServices(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "xds.sock")
+	startService(t, &configv1.Config{
+		Host: "unix://" + socket,
+		Services: []configv1.DiscoveryService{
+			configv1.DiscoveryService_DISCOVERY_SERVICE_RTDS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_RTDS, // Duplicates are ignored.
+		},
+		Resources: runtimeResources(t, "version", "v1"),
+	})
+	conn := dial(t, socket)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rtds, err := runtimeservice.NewRuntimeDiscoveryServiceClient(conn).StreamRuntime(ctx)
+	require.NoError(t, err)
+	require.NoError(t, rtds.Send(&discoveryservice.DiscoveryRequest{
+		Node:          &core.Node{Id: "test"},
+		ResourceNames: []string{"rtds"},
+	}))
+	res, err := rtds.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "v1", runtimeLayer(t, res, "version"))
+
+	// ADS is not registered.
+	_, err = tryFetch(conn, resource.RuntimeType, "rtds")
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestValidateDiscoveryServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []configv1.DiscoveryService
+		err      bool
+	}{
+		{name: "all"},
+		{name: "some", services: []configv1.DiscoveryService{
+			configv1.DiscoveryService_DISCOVERY_SERVICE_ADS,
+			configv1.DiscoveryService_DISCOVERY_SERVICE_SDS,
+		}},
+		{name: "unspecified", services: []configv1.DiscoveryService{
+			configv1.DiscoveryService_DISCOVERY_SERVICE_UNSPECIFIED,
+		}, err: true},
+		{name: "unknown", services: []configv1.DiscoveryService{
+			configv1.DiscoveryService(100),
+		}, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := xds.New(&run.Group{}, &xds.Config{Config: &configv1.Config{Services: test.services}})
+			err := s.Validate()
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// startService starts the xDS service with the given config, serving the admin endpoints on a unix
+// domain socket. The service is set up by the given functions before it is validated. It returns the
+// service and a client of the admin endpoints.
+func startService(t *testing.T, cfg *configv1.Config, setup ...func(*xds.Service)) (*xds.Service, *adminClient) {
+	return startServiceWith(t, &xds.Config{Config: cfg}, setup...)
+}
+
+// startServiceWith is like startService, with the given service config, e.g. to set the logger.
+func startServiceWith(t *testing.T, config *xds.Config, setup ...func(*xds.Service)) (*xds.Service, *adminClient) {
+	dir := t.TempDir()
+	cfg := config.Config
+	if cfg.Host == "" {
+		cfg.Host = "127.0.0.1"
+	}
+	adminSocket := filepath.Join(dir, "admin.sock")
+	if cfg.Admin == nil {
+		cfg.Admin = &configv1.Admin{}
+	}
+	cfg.Admin.Host = "unix://" + adminSocket
+
+	s := xds.New(&run.Group{}, config)
+	for _, f := range setup {
+		f(s)
+	}
+	require.NoError(t, s.Validate())
+	require.NoError(t, s.PreRun())
+	served := make(chan error, 1)
+	go func() {
+		served <- s.Serve()
+	}()
+	t.Cleanup(func() {
+		s.GracefulStop()
+		<-served
+	})
+
+	return s, &adminClient{t: t, client: &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", adminSocket)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}}
+}
+
+type adminClient struct {
+	t      *testing.T
+	client *http.Client
+}
+
+// get returns the body of a successful GET request.
+func (c *adminClient) get(path string) string {
+	res, err := c.client.Get("http://xds" + path)
+	require.NoError(c.t, err)
+	code, body := c.read(res)
+	require.Equal(c.t, http.StatusOK, code, body)
+	return body
+}
+
+// post returns the status code and the body of a POST request with a JSON body.
+func (c *adminClient) post(path, body string) (int, string) {
+	res, err := c.client.Post("http://xds"+path, "application/json", strings.NewReader(body))
+	require.NoError(c.t, err)
+	return c.read(res)
+}
+
+func (c *adminClient) read(res *http.Response) (int, string) {
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	b, err := io.ReadAll(res.Body)
+	require.NoError(c.t, err)
+	return res.StatusCode, string(b)
+}
+
+// withConfigFile writes the config to the file at the given path, and sets the service to load its
+// config from the file.
+func withConfigFile(t *testing.T, cfg *configv1.Config, path string) func(*xds.Service) {
+	return func(s *xds.Service) {
+		writeConfigFile(t, cfg, path)
+		require.NoError(t, s.FlagSet().Parse([]string{"--xds-service-config", path}))
+	}
+}
+
+// writeConfigFile writes the config as JSON, which is also valid YAML.
+func writeConfigFile(t *testing.T, cfg *configv1.Config, path string) {
+	b, err := protojson.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+}
+
+// dial returns a client connection to the xDS server listening on the unix domain socket.
+func dial(t *testing.T, socket string, opts ...grpc.DialOption) *grpc.ClientConn {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.Dial("unix://"+socket, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// fetch returns the first response to an ADS request of the named resources of the given type.
+func fetch(t *testing.T, conn *grpc.ClientConn, typeURL string, names ...string) *discoveryservice.DiscoveryResponse {
+	res, err := tryFetch(conn, typeURL, names...)
+	require.NoError(t, err)
+	return res
+}
+
+func tryFetch(conn *grpc.ClientConn, typeURL string, names ...string) (*discoveryservice.DiscoveryResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ads, err := discoveryservice.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = ads.Send(&discoveryservice.DiscoveryRequest{
+		Node:          &core.Node{Id: "test"},
+		TypeUrl:       typeURL,
+		ResourceNames: names,
+	}); err != nil {
+		return nil, err
+	}
+	return ads.Recv()
+}
+
+// runtimeLayer returns the value of the key of the runtime layer of the response.
+func runtimeLayer(t *testing.T, res *discoveryservice.DiscoveryResponse, key string) string {
+	require.Len(t, res.Resources, 1)
+	var layer runtimeservice.Runtime
+	require.NoError(t, res.Resources[0].UnmarshalTo(&layer))
+	return layer.Layer.Fields[key].GetStringValue()
+}
+
+// writeRuntimeFile writes a resource file of a runtime layer named rtds, with the given version.
+func writeRuntimeFile(t *testing.T, path, version string) {
+	content := "\"@type\": type.googleapis.com/envoy.service.runtime.v3.Runtime\nname: rtds\nlayer:\n  version: " + version + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// runtimeResources returns the resources of a runtime layer named rtds, with the given key and value.
+func runtimeResources(t *testing.T, key, value string) *configv1.Resources {
+	layer, err := structpb.NewStruct(map[string]interface{}{key: value})
+	require.NoError(t, err)
+	return &configv1.Resources{RuntimeLayers: []*configv1.RuntimeLayer{{Name: "rtds", Layer: layer}}}
+}
+
+// recorder records the logged messages.
+type recorder struct {
+	telemetry.Logger
+
+	mu      sync.Mutex
+	entries []entry
+}
+
+// entry is a logged message.
+type entry struct {
+	level string
+	msg   string
+	err   error
+	kv    []interface{}
+}
+
+func (r *recorder) Debug(msg string, kv ...interface{}) { r.record(entry{"debug", msg, nil, kv}) }
+
+func (r *recorder) Info(msg string, kv ...interface{}) { r.record(entry{"info", msg, nil, kv}) }
+
+func (r *recorder) Error(msg string, err error, kv ...interface{}) {
+	r.record(entry{"error", msg, err, kv})
+}
+
+func (r *recorder) record(e entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// count returns the number of times the message is logged.
+func (r *recorder) count(msg string) int {
+	return len(r.find(func(e entry) bool { return e.msg == msg }))
+}
+
+// find returns the logged entries that match.
+func (r *recorder) find(match func(entry) bool) []entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []entry
+	for _, e := range r.entries {
+		if match(e) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"sync"
+
+	"github.com/tetratelabs/telemetry"
+)
+
+// metrics holds the xDS server metrics. The metrics are bootstrapped once the global metric sink is
+// registered (see: telemetry.SetGlobalMetricSink), until then recording is a no-op.
+type metrics struct {
+	mu sync.RWMutex
+
+	typeURL telemetry.Label
+
+	openStreams telemetry.Metric
+	requests    telemetry.Metric
+	responses   telemetry.Metric
+	acks        telemetry.Metric
+	nacks       telemetry.Metric
+}
+
+func newMetrics() *metrics {
+	m := &metrics{}
+	telemetry.ToGlobalMetricSink(func(sink telemetry.MetricSink) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.typeURL = sink.NewLabel("type_url")
+		labels := telemetry.WithLabels(m.typeURL)
+		m.openStreams = sink.NewGauge("xds_open_streams", "Number of open xDS streams", labels)
+		m.requests = sink.NewSum("xds_requests_total", "Number of received xDS requests", labels)
+		m.responses = sink.NewSum("xds_responses_total", "Number of sent xDS responses", labels)
+		m.acks = sink.NewSum("xds_acks_total", "Number of ACKed xDS responses", labels)
+		m.nacks = sink.NewSum("xds_nacks_total", "Number of NACKed (rejected) xDS responses", labels)
+	})
+	return m
+}
+
+// record records the value to the metric selected by the given function, with the type URL label.
+func (m *metrics) record(metric func(*metrics) telemetry.Metric, typeURL string, value float64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	selected := metric(m)
+	if selected == nil {
+		return
+	}
+	selected.With(m.typeURL.Insert(typeURL)).Record(value)
+}
+
+func (m *metrics) increment(metric func(*metrics) telemetry.Metric, typeURL string) {
+	m.record(metric, typeURL, 1)
+}
+
+func openStreams(m *metrics) telemetry.Metric { return m.openStreams }
+func requests(m *metrics) telemetry.Metric    { return m.requests }
+func responses(m *metrics) telemetry.Metric   { return m.responses }
+func acks(m *metrics) telemetry.Metric        { return m.acks }
+func nacks(m *metrics) telemetry.Metric       { return m.nacks }
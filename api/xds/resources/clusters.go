@@ -0,0 +1,185 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"sort"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	upstreamhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultConnectTimeout is the connect timeout of the built clusters.
+const DefaultConnectTimeout = 5 * time.Second
+
+const httpProtocolOptions = "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
+
+// Endpoint is an upstream host and port.
+type Endpoint struct {
+	Host string `json:"host"`
+	Port uint32 `json:"port"`
+	// Priority of the endpoint, 0 is the highest. The priorities of the endpoints of a cluster are
+	// expected to be contiguous, starting from 0.
+	Priority uint32 `json:"priority,omitempty"`
+	// Weight is the load balancing weight of the endpoint within its priority. Zero means unset,
+	// which is 1 for Envoy.
+	Weight uint32 `json:"weight,omitempty"`
+}
+
+// StaticCluster returns a STATIC cluster of the given endpoints. The endpoint hosts are required to
+// be IP addresses.
+func StaticCluster(name string, endpoints ...Endpoint) *cluster.Cluster {
+	return newCluster(name, cluster.Cluster_STATIC, LoadAssignment(name, endpoints...))
+}
+
+// StrictDNSCluster returns a STRICT_DNS cluster of the given endpoints. The endpoint hosts are
+// resolved, and all of the resolved addresses are the upstream hosts of the cluster.
+func StrictDNSCluster(name string, endpoints ...Endpoint) *cluster.Cluster {
+	c := newCluster(name, cluster.Cluster_STRICT_DNS, LoadAssignment(name, endpoints...))
+	c.DnsLookupFamily = cluster.Cluster_V4_ONLY
+	return c
+}
+
+// EDSCluster returns an EDS cluster, its load assignment is fetched from the given config source.
+// The load assignment is expected to be named after the cluster, see: LoadAssignment.
+func EDSCluster(name string, source *core.ConfigSource) *cluster.Cluster {
+	c := newCluster(name, cluster.Cluster_EDS, nil)
+	c.EdsClusterConfig = &cluster.Cluster_EdsClusterConfig{EdsConfig: source}
+	return c
+}
+
+// GRPCCluster returns a cluster of a gRPC server at the given address, which is either a TCP address
+// (host:port), or a unix domain socket address, e.g. unix:///var/run/xds.sock.
+func GRPCCluster(name, address string) (*cluster.Cluster, error) {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	var c *cluster.Cluster
+	if socket := parsed.GetSocketAddress(); socket != nil {
+		c = StrictDNSCluster(name, Endpoint{Host: socket.Address, Port: socket.GetPortValue()})
+	} else {
+		// A unix domain socket address can only be used by a STATIC cluster.
+		c = newCluster(name, cluster.Cluster_STATIC, &endpoint.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*endpoint.LbEndpoint{{
+					HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+						Endpoint: &endpoint.Endpoint{Address: parsed},
+					},
+				}},
+			}},
+		})
+	}
+	return WithHTTP2(c), nil
+}
+
+// LoadAssignment returns the load assignment of the given endpoints for a cluster. The endpoints are
+// grouped by their priorities.
+func LoadAssignment(clusterName string, endpoints ...Endpoint) *endpoint.ClusterLoadAssignment {
+	var localities []*endpoint.LocalityLbEndpoints
+	byPriority := make(map[uint32]*endpoint.LocalityLbEndpoints)
+	for _, e := range endpoints {
+		locality, ok := byPriority[e.Priority]
+		if !ok {
+			locality = &endpoint.LocalityLbEndpoints{Priority: e.Priority}
+			byPriority[e.Priority] = locality
+			localities = append(localities, locality)
+		}
+		lbEndpoint := &endpoint.LbEndpoint{
+			HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+				Endpoint: &endpoint.Endpoint{
+					Address: SocketAddress(e.Host, e.Port),
+				},
+			},
+		}
+		if e.Weight > 0 {
+			lbEndpoint.LoadBalancingWeight = wrapperspb.UInt32(e.Weight)
+		}
+		locality.LbEndpoints = append(locality.LbEndpoints, lbEndpoint)
+	}
+	if len(localities) == 0 {
+		localities = []*endpoint.LocalityLbEndpoints{{}}
+	}
+	sort.Slice(localities, func(i, j int) bool {
+		return localities[i].Priority < localities[j].Priority
+	})
+	return &endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints:   localities,
+	}
+}
+
+// UpstreamTLS returns a transport socket for a cluster to originate TLS connections, with the given
+// SNI. When validationSecret is not empty, the upstream certificates are verified with the
+// validation context served by SDS as the secret with that name, through the given config source.
+//
+//	c := resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 443})
+//	c.TransportSocket = resources.UpstreamTLS("example.com", "", nil)
+func UpstreamTLS(sni, validationSecret string, source *core.ConfigSource) *core.TransportSocket {
+	context := &tls.UpstreamTlsContext{
+		Sni:              sni,
+		CommonTlsContext: &tls.CommonTlsContext{},
+	}
+	if validationSecret != "" {
+		context.CommonTlsContext.ValidationContextType = &tls.CommonTlsContext_ValidationContextSdsSecretConfig{
+			ValidationContextSdsSecretConfig: &tls.SdsSecretConfig{
+				Name:      validationSecret,
+				SdsConfig: source,
+			},
+		}
+	}
+	return &core.TransportSocket{
+		Name:       wellknown.TransportSocketTLS,
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: mustAny(context)},
+	}
+}
+
+// WithHTTP2 sets the cluster to use HTTP/2 for the upstream connections, e.g. for a gRPC upstream.
+func WithHTTP2(c *cluster.Cluster) *cluster.Cluster {
+	options := &upstreamhttp.HttpProtocolOptions{
+		UpstreamProtocolOptions: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: &core.Http2ProtocolOptions{},
+				},
+			},
+		},
+	}
+	if c.TypedExtensionProtocolOptions == nil {
+		c.TypedExtensionProtocolOptions = make(map[string]*anypb.Any)
+	}
+	c.TypedExtensionProtocolOptions[httpProtocolOptions] = mustAny(options)
+	return c
+}
+
+func newCluster(name string, discoveryType cluster.Cluster_DiscoveryType,
+	assignment *endpoint.ClusterLoadAssignment) *cluster.Cluster {
+	return &cluster.Cluster{
+		Name:                 name,
+		ConnectTimeout:       durationpb.New(DefaultConnectTimeout),
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: discoveryType},
+		LbPolicy:             cluster.Cluster_ROUND_ROBIN,
+		LoadAssignment:       assignment,
+	}
+}
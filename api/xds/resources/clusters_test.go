@@ -0,0 +1,175 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources_test
+
+import (
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestClusters(t *testing.T) {
+	endpoints := []resources.Endpoint{{Host: "10.0.0.1", Port: 8080}, {Host: "10.0.0.2", Port: 8081}}
+	tests := []struct {
+		name          string
+		cluster       *cluster.Cluster
+		discoveryType cluster.Cluster_DiscoveryType
+		endpoints     []resources.Endpoint
+	}{
+		{
+			name:          "static",
+			cluster:       resources.StaticCluster("upstream", endpoints...),
+			discoveryType: cluster.Cluster_STATIC,
+			endpoints:     endpoints,
+		},
+		{
+			name:          "strict dns",
+			cluster:       resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 80}),
+			discoveryType: cluster.Cluster_STRICT_DNS,
+			endpoints:     []resources.Endpoint{{Host: "example.com", Port: 80}},
+		},
+		{
+			name:          "eds",
+			cluster:       resources.EDSCluster("upstream", resources.ADSConfigSource()),
+			discoveryType: cluster.Cluster_EDS,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := test.cluster
+			require.NoError(t, c.ValidateAll())
+			require.Equal(t, "upstream", c.Name)
+			require.Equal(t, test.discoveryType, c.GetType())
+			require.Equal(t, resources.DefaultConnectTimeout, c.ConnectTimeout.AsDuration())
+			require.Equal(t, cluster.Cluster_ROUND_ROBIN, c.LbPolicy)
+
+			if test.endpoints == nil {
+				// The load assignment of an EDS cluster is fetched from its config source.
+				require.Nil(t, c.LoadAssignment)
+				require.True(t, proto.Equal(resources.ADSConfigSource(), c.EdsClusterConfig.EdsConfig))
+				return
+			}
+			require.True(t, proto.Equal(resources.LoadAssignment("upstream", test.endpoints...), c.LoadAssignment))
+		})
+	}
+
+	// The hosts of a STRICT_DNS cluster are resolved to IPv4 addresses only.
+	require.Equal(t, cluster.Cluster_V4_ONLY, tests[1].cluster.DnsLookupFamily)
+}
+
+func TestLoadAssignment(t *testing.T) {
+	assignment := resources.LoadAssignment("upstream",
+		resources.Endpoint{Host: "10.0.0.1", Port: 8080}, resources.Endpoint{Host: "10.0.0.2", Port: 8081})
+	require.NoError(t, assignment.ValidateAll())
+	require.Equal(t, "upstream", assignment.ClusterName)
+	require.Len(t, assignment.Endpoints, 1)
+
+	lbEndpoints := assignment.Endpoints[0].LbEndpoints
+	require.Len(t, lbEndpoints, 2)
+	for i, expected := range []string{"10.0.0.1", "10.0.0.2"} {
+		address := lbEndpoints[i].GetEndpoint().Address.GetSocketAddress()
+		require.Equal(t, expected, address.Address)
+		require.Equal(t, uint32(8080+i), address.GetPortValue())
+	}
+
+	// The endpoints are grouped by priority, with their weights.
+	assignment = resources.LoadAssignment("upstream",
+		resources.Endpoint{Host: "10.0.0.3", Port: 8080, Priority: 1},
+		resources.Endpoint{Host: "10.0.0.1", Port: 8080, Weight: 3},
+		resources.Endpoint{Host: "10.0.0.2", Port: 8080, Weight: 1})
+	require.NoError(t, assignment.ValidateAll())
+	require.Len(t, assignment.Endpoints, 2)
+	require.Equal(t, uint32(0), assignment.Endpoints[0].Priority)
+	require.Len(t, assignment.Endpoints[0].LbEndpoints, 2)
+	require.Equal(t, uint32(3), assignment.Endpoints[0].LbEndpoints[0].LoadBalancingWeight.GetValue())
+	require.Equal(t, uint32(1), assignment.Endpoints[0].LbEndpoints[1].LoadBalancingWeight.GetValue())
+	require.Equal(t, uint32(1), assignment.Endpoints[1].Priority)
+	require.Len(t, assignment.Endpoints[1].LbEndpoints, 1)
+	require.Nil(t, assignment.Endpoints[1].LbEndpoints[0].LoadBalancingWeight)
+
+	// No endpoints still makes a valid (empty) load assignment.
+	require.NoError(t, resources.LoadAssignment("upstream").ValidateAll())
+}
+
+func TestUpstreamTLS(t *testing.T) {
+	tests := []struct {
+		name             string
+		validationSecret string
+	}{
+		{name: "without validation"},
+		{name: "with validation", validationSecret: "upstream-ca"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 443})
+			c.TransportSocket = resources.UpstreamTLS("example.com", test.validationSecret, resources.ADSConfigSource())
+			require.NoError(t, c.ValidateAll())
+			require.Equal(t, wellknown.TransportSocketTLS, c.TransportSocket.Name)
+
+			var context tls.UpstreamTlsContext
+			require.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(&context))
+			require.Equal(t, "example.com", context.Sni)
+
+			sds := context.CommonTlsContext.GetValidationContextSdsSecretConfig()
+			if test.validationSecret == "" {
+				require.Nil(t, sds)
+				return
+			}
+			require.Equal(t, test.validationSecret, sds.Name)
+			require.True(t, proto.Equal(resources.ADSConfigSource(), sds.SdsConfig))
+		})
+	}
+}
+
+func TestGRPCCluster(t *testing.T) {
+	tests := []struct {
+		address       string
+		discoveryType cluster.Cluster_DiscoveryType
+		host          string
+		port          uint32
+		pipe          string
+	}{
+		{address: "127.0.0.1:18000", discoveryType: cluster.Cluster_STRICT_DNS, host: "127.0.0.1", port: 18000},
+		{address: "xds.local:18000", discoveryType: cluster.Cluster_STRICT_DNS, host: "xds.local", port: 18000},
+		// A unix domain socket address can only be used by a STATIC cluster.
+		{address: "unix:///var/run/xds.sock", discoveryType: cluster.Cluster_STATIC, pipe: "/var/run/xds.sock"},
+	}
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			c, err := resources.GRPCCluster(resources.XDSClusterName, test.address)
+			require.NoError(t, err)
+			require.NoError(t, c.ValidateAll())
+			require.Equal(t, resources.XDSClusterName, c.Name)
+			require.Equal(t, test.discoveryType, c.GetType())
+			require.Equal(t, resources.XDSClusterName, c.LoadAssignment.ClusterName)
+
+			address := c.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address
+			require.Equal(t, test.host, address.GetSocketAddress().GetAddress())
+			require.Equal(t, test.port, address.GetSocketAddress().GetPortValue())
+			require.Equal(t, test.pipe, address.GetPipe().GetPath())
+			// gRPC requires HTTP/2.
+			require.Contains(t, c.TypedExtensionProtocolOptions, "envoy.extensions.upstreams.http.v3.HttpProtocolOptions")
+		})
+	}
+
+	_, err := resources.GRPCCluster(resources.XDSClusterName, "127.0.0.1")
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	router "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+)
+
+// HTTPConnectionManager returns an HTTP connection manager that routes the requests with the route
+// configuration of the given name, fetched from the config source.
+func HTTPConnectionManager(statPrefix, routeName string, source *core.ConfigSource) *hcm.HttpConnectionManager {
+	return &hcm.HttpConnectionManager{
+		CodecType:  hcm.HttpConnectionManager_AUTO,
+		StatPrefix: statPrefix,
+		RouteSpecifier: &hcm.HttpConnectionManager_Rds{
+			Rds: &hcm.Rds{
+				ConfigSource:    source,
+				RouteConfigName: routeName,
+			},
+		},
+		HttpFilters: []*hcm.HttpFilter{{
+			Name:       wellknown.Router,
+			ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: mustAny(&router.Router{})},
+		}},
+	}
+}
+
+// HTTPListener returns a plaintext listener on the address that handles the requests with the
+// given HTTP connection manager.
+func HTTPListener(name string, address *core.Address, manager *hcm.HttpConnectionManager) *listener.Listener {
+	return &listener.Listener{
+		Name:         name,
+		Address:      address,
+		FilterChains: []*listener.FilterChain{{Filters: []*listener.Filter{httpFilter(manager)}}},
+	}
+}
+
+// HTTPSListener returns a listener like HTTPListener, that terminates TLS with the certificate
+// served by SDS as the secret with the given name, through the given config source.
+func HTTPSListener(name string, address *core.Address, manager *hcm.HttpConnectionManager,
+	certificateSecret string, source *core.ConfigSource) *listener.Listener {
+	context := &tls.DownstreamTlsContext{
+		CommonTlsContext: &tls.CommonTlsContext{
+			TlsCertificateSdsSecretConfigs: []*tls.SdsSecretConfig{{
+				Name:      certificateSecret,
+				SdsConfig: source,
+			}},
+			AlpnProtocols: []string{"h2", "http/1.1"},
+		},
+	}
+	return &listener.Listener{
+		Name:    name,
+		Address: address,
+		FilterChains: []*listener.FilterChain{{
+			Filters: []*listener.Filter{httpFilter(manager)},
+			TransportSocket: &core.TransportSocket{
+				Name:       wellknown.TransportSocketTLS,
+				ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: mustAny(context)},
+			},
+		}},
+	}
+}
+
+// TCPProxyListener returns a listener on the address that proxies the connections to the cluster.
+func TCPProxyListener(name string, address *core.Address, clusterName string) *listener.Listener {
+	proxy := &tcp.TcpProxy{
+		StatPrefix:       name,
+		ClusterSpecifier: &tcp.TcpProxy_Cluster{Cluster: clusterName},
+	}
+	return &listener.Listener{
+		Name:    name,
+		Address: address,
+		FilterChains: []*listener.FilterChain{{
+			Filters: []*listener.Filter{{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &listener.Filter_TypedConfig{TypedConfig: mustAny(proxy)},
+			}},
+		}},
+	}
+}
+
+func httpFilter(manager *hcm.HttpConnectionManager) *listener.Filter {
+	return &listener.Filter{
+		Name:       wellknown.HTTPConnectionManager,
+		ConfigType: &listener.Filter_TypedConfig{TypedConfig: mustAny(manager)},
+	}
+}
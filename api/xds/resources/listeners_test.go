@@ -0,0 +1,113 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources_test
+
+import (
+	"testing"
+
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestHTTPConnectionManager(t *testing.T) {
+	manager := resources.HTTPConnectionManager("http", "local_route", resources.ADSConfigSource())
+	require.NoError(t, manager.ValidateAll())
+	require.Equal(t, "http", manager.StatPrefix)
+	require.Equal(t, hcm.HttpConnectionManager_AUTO, manager.CodecType)
+	require.Equal(t, "local_route", manager.GetRds().RouteConfigName)
+	require.True(t, proto.Equal(resources.ADSConfigSource(), manager.GetRds().ConfigSource))
+	// The router filter is required to forward the requests.
+	require.Len(t, manager.HttpFilters, 1)
+	require.Equal(t, wellknown.Router, manager.HttpFilters[0].Name)
+}
+
+func TestListeners(t *testing.T) {
+	address := resources.SocketAddress("0.0.0.0", 10000)
+	manager := resources.HTTPConnectionManager("http", "local_route", resources.ADSConfigSource())
+	tests := []struct {
+		name     string
+		listener *listener.Listener
+		// The expected name of the only network filter.
+		filter string
+		tls    bool
+	}{
+		{
+			name:     "http",
+			listener: resources.HTTPListener("listener_0", address, manager),
+			filter:   wellknown.HTTPConnectionManager,
+		},
+		{
+			name:     "https",
+			listener: resources.HTTPSListener("listener_0", address, manager, "server-cert", resources.ADSConfigSource()),
+			filter:   wellknown.HTTPConnectionManager,
+			tls:      true,
+		},
+		{
+			name:     "tcp proxy",
+			listener: resources.TCPProxyListener("listener_0", address, "upstream"),
+			filter:   wellknown.TCPProxy,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := test.listener
+			require.NoError(t, l.ValidateAll())
+			require.Equal(t, "listener_0", l.Name)
+			require.True(t, proto.Equal(address, l.Address))
+			require.Len(t, l.FilterChains, 1)
+
+			chain := l.FilterChains[0]
+			require.Len(t, chain.Filters, 1)
+			require.Equal(t, test.filter, chain.Filters[0].Name)
+
+			switch test.filter {
+			case wellknown.HTTPConnectionManager:
+				var decoded hcm.HttpConnectionManager
+				require.NoError(t, chain.Filters[0].GetTypedConfig().UnmarshalTo(&decoded))
+				require.True(t, proto.Equal(manager, &decoded))
+			case wellknown.TCPProxy:
+				var decoded tcp.TcpProxy
+				require.NoError(t, chain.Filters[0].GetTypedConfig().UnmarshalTo(&decoded))
+				require.Equal(t, "listener_0", decoded.StatPrefix)
+				require.Equal(t, "upstream", decoded.GetCluster())
+			}
+
+			if !test.tls {
+				require.Nil(t, chain.TransportSocket)
+				return
+			}
+			require.Equal(t, wellknown.TransportSocketTLS, chain.TransportSocket.Name)
+			var context tls.DownstreamTlsContext
+			require.NoError(t, chain.TransportSocket.GetTypedConfig().UnmarshalTo(&context))
+			configs := context.CommonTlsContext.TlsCertificateSdsSecretConfigs
+			require.Len(t, configs, 1)
+			require.Equal(t, "server-cert", configs[0].Name)
+			require.True(t, proto.Equal(resources.ADSConfigSource(), configs[0].SdsConfig))
+			require.Equal(t, []string{"h2", "http/1.1"}, context.CommonTlsContext.AlpnProtocols)
+		})
+	}
+
+	// A listener on a unix domain socket is valid too.
+	l := resources.HTTPListener("listener_0", resources.PipeAddress("/var/run/envoy.sock"), manager)
+	require.NoError(t, l.ValidateAll())
+	require.Equal(t, "/var/run/envoy.sock", l.Address.GetPipe().Path)
+}
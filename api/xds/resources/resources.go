@@ -0,0 +1,129 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resources provides builders of Envoy v3 API resources, to compose the snapshots served by
+// the xDS service in Go, e.g.:
+//
+//	source := resources.ADSConfigSource()
+//	upstream := resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 80})
+//	routes := resources.RouteConfiguration("local_route",
+//		resources.VirtualHost("local", []string{"*"}, resources.RouteToCluster("/", "upstream")))
+//	listener := resources.HTTPListener("listener_0", resources.SocketAddress("0.0.0.0", 10000),
+//		resources.HTTPConnectionManager("http", "local_route", source))
+//
+//	err := service.SetResources(xds.DefaultNodeGroup, map[resource.Type][]types.Resource{
+//		resource.ClusterType:  {upstream},
+//		resource.RouteType:    {routes},
+//		resource.ListenerType: {listener},
+//	})
+//
+// The returned resources are plain messages, hence they can be further modified before use.
+package resources
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/dio/rundown/internal/listener"
+)
+
+// XDSClusterName is the name of the static cluster of the xDS server in an Envoy bootstrap.
+const XDSClusterName = "xds-grpc"
+
+// ADSConfigSource returns a config source to fetch resources through the ADS stream configured in
+// the Envoy bootstrap.
+func ADSConfigSource() *core.ConfigSource {
+	return &core.ConfigSource{
+		ResourceApiVersion:    resource.DefaultAPIVersion,
+		ConfigSourceSpecifier: &core.ConfigSource_Ads{Ads: &core.AggregatedConfigSource{}},
+	}
+}
+
+// GRPCConfigSource returns a config source to fetch resources from the xDS server, which is
+// reachable through the given cluster, e.g. XDSClusterName.
+func GRPCConfigSource(clusterName string) *core.ConfigSource {
+	return &core.ConfigSource{
+		ResourceApiVersion: resource.DefaultAPIVersion,
+		ConfigSourceSpecifier: &core.ConfigSource_ApiConfigSource{
+			ApiConfigSource: &core.ApiConfigSource{
+				TransportApiVersion:       resource.DefaultAPIVersion,
+				ApiType:                   core.ApiConfigSource_GRPC,
+				SetNodeOnFirstMessageOnly: true,
+				GrpcServices: []*core.GrpcService{{
+					TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+						EnvoyGrpc: &core.GrpcService_EnvoyGrpc{ClusterName: clusterName},
+					},
+				}},
+			},
+		},
+	}
+}
+
+// SocketAddress returns a TCP socket address.
+func SocketAddress(host string, port uint32) *core.Address {
+	return &core.Address{
+		Address: &core.Address_SocketAddress{
+			SocketAddress: &core.SocketAddress{
+				Protocol: core.SocketAddress_TCP,
+				Address:  host,
+				PortSpecifier: &core.SocketAddress_PortValue{
+					PortValue: port,
+				},
+			},
+		},
+	}
+}
+
+// PipeAddress returns a unix domain socket address.
+func PipeAddress(path string) *core.Address {
+	return &core.Address{
+		Address: &core.Address_Pipe{
+			Pipe: &core.Pipe{Path: path},
+		},
+	}
+}
+
+// ParseAddress parses a TCP address (host:port), or a unix domain socket address, e.g.
+// unix:///var/run/xds.sock.
+func ParseAddress(address string) (*core.Address, error) {
+	if listener.IsUnix(address) {
+		return PipeAddress(strings.TrimPrefix(address, listener.UnixPrefix)), nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port of %s: %w", address, err)
+	}
+	return SocketAddress(host, uint32(parsed)), nil
+}
+
+// mustAny wraps the message as an Any. It panics only when the message type can't be resolved,
+// which never happens for the linked Envoy API messages.
+func mustAny(m proto.Message) *anypb.Any {
+	wrapped, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+	return wrapped
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources_test
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		host    string
+		port    uint32
+		pipe    string
+	}{
+		{address: "127.0.0.1:9901", host: "127.0.0.1", port: 9901},
+		{address: "[::1]:9901", host: "::1", port: 9901},
+		{address: "localhost:0", host: "localhost"},
+		{address: "unix:///var/run/xds.sock", pipe: "/var/run/xds.sock"},
+	}
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			address, err := resources.ParseAddress(test.address)
+			require.NoError(t, err)
+			require.NoError(t, address.Validate())
+			require.Equal(t, test.host, address.GetSocketAddress().GetAddress())
+			require.Equal(t, test.port, address.GetSocketAddress().GetPortValue())
+			require.Equal(t, test.pipe, address.GetPipe().GetPath())
+		})
+	}
+
+	for _, address := range []string{"127.0.0.1", "127.0.0.1:65536", "127.0.0.1:port", ""} {
+		_, err := resources.ParseAddress(address)
+		require.Error(t, err, address)
+	}
+}
+
+func TestConfigSources(t *testing.T) {
+	ads := resources.ADSConfigSource()
+	require.NoError(t, ads.ValidateAll())
+	require.Equal(t, resource.DefaultAPIVersion, ads.ResourceApiVersion)
+	require.NotNil(t, ads.GetAds())
+
+	grpc := resources.GRPCConfigSource(resources.XDSClusterName)
+	require.NoError(t, grpc.ValidateAll())
+	require.Equal(t, resource.DefaultAPIVersion, grpc.ResourceApiVersion)
+	api := grpc.GetApiConfigSource()
+	require.Equal(t, core.ApiConfigSource_GRPC, api.ApiType)
+	require.Equal(t, resource.DefaultAPIVersion, api.TransportApiVersion)
+	require.Len(t, api.GrpcServices, 1)
+	require.Equal(t, resources.XDSClusterName, api.GrpcServices[0].GetEnvoyGrpc().ClusterName)
+}
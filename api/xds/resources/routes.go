@@ -0,0 +1,109 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+)
+
+// RouteConfiguration returns a route configuration of the given virtual hosts.
+func RouteConfiguration(name string, hosts ...*route.VirtualHost) *route.RouteConfiguration {
+	return &route.RouteConfiguration{
+		Name:         name,
+		VirtualHosts: hosts,
+	}
+}
+
+// VirtualHost returns a virtual host serving the given domains, e.g. "*" or "*.example.com", with
+// the given routes. The routes are matched in order.
+func VirtualHost(name string, domains []string, routes ...*route.Route) *route.VirtualHost {
+	return &route.VirtualHost{
+		Name:    name,
+		Domains: domains,
+		Routes:  routes,
+	}
+}
+
+// RouteToCluster returns a route that forwards the requests with the given path prefix, and
+// matching all of the given headers, to the cluster.
+func RouteToCluster(prefix, clusterName string, headers ...*route.HeaderMatcher) *route.Route {
+	return &route.Route{
+		Match: &route.RouteMatch{
+			PathSpecifier: &route.RouteMatch_Prefix{Prefix: prefix},
+			Headers:       headers,
+		},
+		Action: &route.Route_Route{
+			Route: &route.RouteAction{
+				ClusterSpecifier: &route.RouteAction_Cluster{Cluster: clusterName},
+			},
+		},
+	}
+}
+
+// WithHostRewrite sets the route to rewrite the host header of the forwarded requests.
+func WithHostRewrite(r *route.Route, host string) *route.Route {
+	if action := r.GetRoute(); action != nil {
+		action.HostRewriteSpecifier = &route.RouteAction_HostRewriteLiteral{HostRewriteLiteral: host}
+	}
+	return r
+}
+
+// ExactHeader matches a header with the exact value.
+func ExactHeader(name, value string) *route.HeaderMatcher {
+	return stringHeader(name, &matcher.StringMatcher{
+		MatchPattern: &matcher.StringMatcher_Exact{Exact: value},
+	})
+}
+
+// PrefixHeader matches a header with a value that starts with the prefix.
+func PrefixHeader(name, prefix string) *route.HeaderMatcher {
+	return stringHeader(name, &matcher.StringMatcher{
+		MatchPattern: &matcher.StringMatcher_Prefix{Prefix: prefix},
+	})
+}
+
+// RegexHeader matches a header with a value that matches the RE2 regular expression.
+func RegexHeader(name, regex string) *route.HeaderMatcher {
+	return stringHeader(name, &matcher.StringMatcher{
+		MatchPattern: &matcher.StringMatcher_SafeRegex{
+			SafeRegex: &matcher.RegexMatcher{
+				EngineType: &matcher.RegexMatcher_GoogleRe2{GoogleRe2: &matcher.RegexMatcher_GoogleRE2{}},
+				Regex:      regex,
+			},
+		},
+	})
+}
+
+// PresentHeader matches when the header is present, regardless of its value.
+func PresentHeader(name string) *route.HeaderMatcher {
+	return &route.HeaderMatcher{
+		Name:                 name,
+		HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{PresentMatch: true},
+	}
+}
+
+// InvertHeader inverts the result of the header matcher.
+func InvertHeader(m *route.HeaderMatcher) *route.HeaderMatcher {
+	m.InvertMatch = true
+	return m
+}
+
+func stringHeader(name string, m *matcher.StringMatcher) *route.HeaderMatcher {
+	return &route.HeaderMatcher{
+		Name:                 name,
+		HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{StringMatch: m},
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources_test
+
+import (
+	"testing"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/api/xds/resources"
+)
+
+func TestRouteConfiguration(t *testing.T) {
+	routes := resources.RouteConfiguration("local_route",
+		resources.VirtualHost("local", []string{"*"},
+			resources.RouteToCluster("/api", "api", resources.ExactHeader("x-version", "v2")),
+			resources.WithHostRewrite(resources.RouteToCluster("/", "upstream"), "example.com"),
+		),
+	)
+	require.NoError(t, routes.ValidateAll())
+	require.Equal(t, "local_route", routes.Name)
+	require.Len(t, routes.VirtualHosts, 1)
+
+	host := routes.VirtualHosts[0]
+	require.Equal(t, "local", host.Name)
+	require.Equal(t, []string{"*"}, host.Domains)
+	require.Len(t, host.Routes, 2)
+
+	// The routes are kept in order.
+	api := host.Routes[0]
+	require.Equal(t, "/api", api.Match.GetPrefix())
+	require.Len(t, api.Match.Headers, 1)
+	require.Equal(t, "api", api.GetRoute().GetCluster())
+	require.Empty(t, api.GetRoute().GetHostRewriteLiteral())
+
+	upstream := host.Routes[1]
+	require.Equal(t, "/", upstream.Match.GetPrefix())
+	require.Empty(t, upstream.Match.Headers)
+	require.Equal(t, "upstream", upstream.GetRoute().GetCluster())
+	require.Equal(t, "example.com", upstream.GetRoute().GetHostRewriteLiteral())
+}
+
+func TestHeaderMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher *route.HeaderMatcher
+		exact   string
+		prefix  string
+		regex   string
+		present bool
+		invert  bool
+	}{
+		{name: "exact", matcher: resources.ExactHeader("x-version", "v2"), exact: "v2"},
+		{name: "prefix", matcher: resources.PrefixHeader("x-version", "v"), prefix: "v"},
+		{name: "regex", matcher: resources.RegexHeader("x-version", "v[0-9]+"), regex: "v[0-9]+"},
+		{name: "present", matcher: resources.PresentHeader("x-version"), present: true},
+		{
+			name:    "invert",
+			matcher: resources.InvertHeader(resources.ExactHeader("x-version", "v1")),
+			exact:   "v1",
+			invert:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := test.matcher
+			require.NoError(t, m.ValidateAll())
+			require.Equal(t, "x-version", m.Name)
+			require.Equal(t, test.exact, m.GetStringMatch().GetExact())
+			require.Equal(t, test.prefix, m.GetStringMatch().GetPrefix())
+			require.Equal(t, test.regex, m.GetStringMatch().GetSafeRegex().GetRegex())
+			require.Equal(t, test.present, m.GetPresentMatch())
+			require.Equal(t, test.invert, m.InvertMatch)
+		})
+	}
+}
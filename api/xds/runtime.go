@@ -0,0 +1,102 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SetRuntime sets the values of the runtime keys in a runtime layer served to the members of a node
+// group, and pushes a new snapshot version. A value is a bool, a number or a string, e.g. false for
+// "envoy.reloadable_features.foo", or 25 for a fractional "routing.traffic_shift.foo". A nil value
+// removes the override of the key. The values override the configured ones, and are kept across
+// config reloads. A layer that is not configured is added. When the snapshots can't be rebuilt with
+// the values, the previous values are restored.
+func (s *Service) SetRuntime(nodeGroup, layer string, values map[string]interface{}) error {
+	if layer == "" {
+		return errors.New("runtime layer name is required")
+	}
+	overrides := make(map[string]*structpb.Value, len(values))
+	for key, value := range values {
+		if value == nil {
+			overrides[key] = nil
+			continue
+		}
+		converted, err := structpb.NewValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid value of runtime key %q: %w", key, err)
+		}
+		overrides[key] = converted
+	}
+
+	previous, err := s.setRuntime(nodeGroup, layer, overrides)
+	if err != nil {
+		return err
+	}
+	// Rebuild the snapshots from the last applied config, with the updated overrides.
+	if err = s.setSnapshots(context.Background(), nil); err != nil {
+		if _, restoreErr := s.setRuntime(nodeGroup, layer, previous); restoreErr != nil {
+			s.cfg.Logger.Error("failed to restore runtime overrides", restoreErr, "node_group", nodeGroup)
+		}
+		return err
+	}
+	return nil
+}
+
+// setRuntime sets the overrides of the layer, and returns the previous values of the overridden keys.
+// A nil previous value means the key was not overridden.
+func (s *Service) setRuntime(nodeGroup, layer string,
+	overrides map[string]*structpb.Value) (map[string]*structpb.Value, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		return nil, ErrNotReady
+	}
+	if !s.nodeGroups.Has(nodeGroup) {
+		return nil, fmt.Errorf("unknown node group %q", nodeGroup)
+	}
+	previous := make(map[string]*structpb.Value, len(overrides))
+	keys := s.runtime[nodeGroup][layer]
+	for key := range overrides {
+		previous[key] = keys[key]
+	}
+	s.runtime.Set(nodeGroup, layer, overrides)
+	return previous, nil
+}
+
+// runtimeOverrides returns the runtime overrides, keyed by node group names, then by layer names,
+// then by runtime keys.
+func (s *Service) runtimeOverrides() map[string]map[string]map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides := make(map[string]map[string]map[string]interface{}, len(s.runtime))
+	for group, layers := range s.runtime {
+		overrides[group] = make(map[string]map[string]interface{}, len(layers))
+		for layer, keys := range layers {
+			values := make(map[string]interface{}, len(keys))
+			for key, value := range keys {
+				values[key] = value.AsInterface()
+			}
+			overrides[group][layer] = values
+		}
+	}
+	return overrides
+}
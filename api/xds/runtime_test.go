@@ -0,0 +1,73 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/api/xds"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+func TestSetRuntime(t *testing.T) {
+	dir := t.TempDir()
+	s, admin := startService(t, &configv1.Config{ResourcesDir: dir})
+
+	require.NoError(t, s.SetRuntime(xds.DefaultNodeGroup, "rtds", map[string]interface{}{"foo": true}))
+	require.Equal(t, map[string]interface{}{"default": map[string]interface{}{"rtds": map[string]interface{}{"foo": true}}},
+		runtimeOverrides(t, admin))
+	require.Error(t, s.SetRuntime("unknown", "rtds", map[string]interface{}{"foo": true}))
+	require.Error(t, s.SetRuntime(xds.DefaultNodeGroup, "", map[string]interface{}{"foo": true}))
+
+	// When the snapshots can't be rebuilt, the previous values are restored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("broken"), 0o600))
+	require.Error(t, s.SetRuntime(xds.DefaultNodeGroup, "rtds", map[string]interface{}{"foo": false, "bar": 1}))
+	require.Equal(t, map[string]interface{}{"default": map[string]interface{}{"rtds": map[string]interface{}{"foo": true}}},
+		runtimeOverrides(t, admin))
+
+	// Runtime updates through the admin server are not allowed by default.
+	code, _ := admin.post("/runtime?layer=rtds", `{"foo": null}`)
+	require.Equal(t, http.StatusForbidden, code)
+}
+
+func TestAdminSetRuntime(t *testing.T) {
+	_, admin := startService(t, &configv1.Config{Admin: &configv1.Admin{AllowRuntimeUpdates: true}})
+
+	code, body := admin.post("/runtime?layer=rtds", `{"foo": false}`)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, map[string]interface{}{"default": map[string]interface{}{"rtds": map[string]interface{}{"foo": false}}},
+		runtimeOverrides(t, admin))
+
+	code, _ = admin.post("/runtime", `{"foo": false}`)
+	require.Equal(t, http.StatusBadRequest, code) // The layer is required.
+	code, _ = admin.post("/runtime?layer=rtds", `[]`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, body = admin.post("/runtime?layer=rtds", `{"foo": null}`)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Empty(t, runtimeOverrides(t, admin))
+}
+
+func runtimeOverrides(t *testing.T, admin *adminClient) map[string]interface{} {
+	var overrides map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(admin.get("/runtime")), &overrides))
+	return overrides
+}
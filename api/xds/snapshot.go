@@ -0,0 +1,101 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cache "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	"github.com/dio/rundown/internal/xds"
+)
+
+// DefaultNodeGroup is the name of the node group for the nodes that match no configured node group.
+const DefaultNodeGroup = xds.DefaultNodeGroup
+
+// ErrNotReady is returned when the snapshots are accessed before the service is prepared to run.
+var ErrNotReady = errors.New("xds service is not ready")
+
+// SetResources sets the resources served to the members of a node group, on top of the resources
+// built from the config: a set resource replaces the built resource of the same type and name. The
+// node group is either DefaultNodeGroup or one of the configured node groups. The resulting
+// snapshot is required to be consistent. The set resources are kept when the snapshots are rebuilt,
+// e.g. when the config file is reloaded, until they are replaced by the next call. Setting no
+// resources removes them. This is safe to be called from other goroutines once the service is
+// prepared to run.
+func (s *Service) SetResources(nodeGroup string, resources map[resource.Type][]types.Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		return ErrNotReady
+	}
+	if !s.nodeGroups.Has(nodeGroup) {
+		return fmt.Errorf("unknown node group %q", nodeGroup)
+	}
+
+	previous, ok := s.resources[nodeGroup]
+	if len(resources) == 0 {
+		delete(s.resources, nodeGroup)
+	} else {
+		s.resources[nodeGroup] = copyResources(resources)
+	}
+	if err := s.setSnapshotsLocked(context.Background(), nil); err != nil {
+		if ok {
+			s.resources[nodeGroup] = previous
+		} else {
+			delete(s.resources, nodeGroup)
+		}
+		return err
+	}
+	return nil
+}
+
+// Snapshot returns the current snapshot served to the members of a node group.
+func (s *Service) Snapshot(nodeGroup string) (cache.ResourceSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		return nil, ErrNotReady
+	}
+	return s.cache.GetSnapshot(nodeGroup)
+}
+
+// ClearSnapshot removes the snapshot of a node group and the resources set through SetResources for
+// it. Its members get no resources until the snapshots are rebuilt, or new resources are set.
+func (s *Service) ClearSnapshot(nodeGroup string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		return
+	}
+	delete(s.resources, nodeGroup)
+	s.cache.ClearSnapshot(nodeGroup)
+}
+
+// copyResources returns a copy of the resources, so the caller can reuse its map and slices.
+func copyResources(resources map[resource.Type][]types.Resource) map[resource.Type][]types.Resource {
+	copied := make(map[resource.Type][]types.Resource, len(resources))
+	for typeURL, list := range resources {
+		copied[typeURL] = append([]types.Resource(nil), list...)
+	}
+	return copied
+}
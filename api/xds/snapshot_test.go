@@ -0,0 +1,148 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cache "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/dio/rundown/api/xds"
+	"github.com/dio/rundown/api/xds/resources"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+func TestSetResources(t *testing.T) {
+	s, _ := startService(t, &configv1.Config{NodeGroups: []*configv1.NodeGroup{
+		{Name: "edge", Match: &configv1.NodeMatch{Clusters: []string{"edge"}}},
+	}})
+
+	initial, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+
+	upstream := resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 80})
+	require.NoError(t, s.SetResources(xds.DefaultNodeGroup, map[resource.Type][]types.Resource{
+		resource.ClusterType: {upstream},
+	}))
+	snapshot, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.NotEqual(t, initial.GetVersion(resource.ClusterType), snapshot.GetVersion(resource.ClusterType))
+	require.Equal(t, map[string]types.Resource{"upstream": upstream}, snapshot.GetResources(resource.ClusterType))
+
+	// The other node groups are not affected.
+	edge, err := s.Snapshot("edge")
+	require.NoError(t, err)
+	require.Empty(t, edge.GetResources(resource.ClusterType))
+
+	// Inconsistent resources are rejected, the current snapshot is kept: the EDS cluster has no load
+	// assignment.
+	require.Error(t, s.SetResources(xds.DefaultNodeGroup, map[resource.Type][]types.Resource{
+		resource.ClusterType: {resources.EDSCluster("backend", resources.ADSConfigSource())},
+	}))
+	current, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, current)
+
+	// A cleared node group has no snapshot, until a new one is set.
+	s.ClearSnapshot(xds.DefaultNodeGroup)
+	_, err = s.Snapshot(xds.DefaultNodeGroup)
+	require.Error(t, err)
+	require.NoError(t, s.SetResources(xds.DefaultNodeGroup, map[resource.Type][]types.Resource{
+		resource.ClusterType: {upstream},
+	}))
+	_, err = s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+}
+
+func TestSetResourcesKeptOnRebuild(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xds.yaml")
+	cfg := &configv1.Config{Resources: runtimeResources(t, "version", "v1")}
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	s, _ := startServiceWith(t, &xds.Config{Config: cfg, Logger: logger}, withConfigFile(t, cfg, path))
+
+	// The set resources are served on top of the configured ones, and replace the configured
+	// resources of the same type and name.
+	upstream := resources.StrictDNSCluster("upstream", resources.Endpoint{Host: "example.com", Port: 80})
+	layer, err := structpb.NewStruct(map[string]interface{}{"version": "set"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetResources(xds.DefaultNodeGroup, map[resource.Type][]types.Resource{
+		resource.ClusterType: {upstream},
+		resource.RuntimeType: {&runtimeservice.Runtime{Name: "rtds", Layer: layer}},
+	}))
+	snapshot, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.Resource{"upstream": upstream}, snapshot.GetResources(resource.ClusterType))
+	require.Equal(t, map[string]string{"rtds": "set"}, runtimeVersions(snapshot))
+
+	// Reloading the config file rebuilds the snapshot, the set resources are kept.
+	layer, err = structpb.NewStruct(map[string]interface{}{"version": "v2"})
+	require.NoError(t, err)
+	cfg.Resources = &configv1.Resources{RuntimeLayers: []*configv1.RuntimeLayer{
+		{Name: "rtds", Layer: layer},
+		{Name: "other", Layer: layer},
+	}}
+	writeConfigFile(t, cfg, path)
+	require.Eventually(t, func() bool {
+		return logger.count("xds service config reloaded") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	snapshot, err = s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.Resource{"upstream": upstream}, snapshot.GetResources(resource.ClusterType))
+	require.Equal(t, map[string]string{"rtds": "set", "other": "v2"}, runtimeVersions(snapshot))
+
+	// Setting no resources removes them.
+	require.NoError(t, s.SetResources(xds.DefaultNodeGroup, nil))
+	snapshot, err = s.Snapshot(xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Empty(t, snapshot.GetResources(resource.ClusterType))
+	require.Equal(t, map[string]string{"rtds": "v2", "other": "v2"}, runtimeVersions(snapshot))
+}
+
+func TestSetResourcesUnknownNodeGroup(t *testing.T) {
+	s, _ := startService(t, &configv1.Config{})
+
+	err := s.SetResources("unknown", map[resource.Type][]types.Resource{})
+	require.EqualError(t, err, `unknown node group "unknown"`)
+	_, err = s.Snapshot("unknown")
+	require.Error(t, err)
+}
+
+func TestSnapshotNotReady(t *testing.T) {
+	s := xds.New(nil, &xds.Config{Config: &configv1.Config{}})
+
+	require.ErrorIs(t, s.SetResources(xds.DefaultNodeGroup, nil), xds.ErrNotReady)
+	_, err := s.Snapshot(xds.DefaultNodeGroup)
+	require.ErrorIs(t, err, xds.ErrNotReady)
+	s.ClearSnapshot(xds.DefaultNodeGroup) // No-op.
+	_, err = s.Status()
+	require.ErrorIs(t, err, xds.ErrNotReady)
+}
+
+// runtimeVersions returns the version keys of the runtime layers of the snapshot, keyed by names.
+func runtimeVersions(snapshot cache.ResourceSnapshot) map[string]string {
+	versions := make(map[string]string)
+	for name, r := range snapshot.GetResources(resource.RuntimeType) {
+		versions[name] = r.(*runtimeservice.Runtime).Layer.Fields["version"].GetStringValue()
+	}
+	return versions
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/dio/rundown/api/xds"
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/testutil"
+)
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "xds.sock")
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	testutil.WriteKeyPair(t, certFile, keyFile, 1)
+	clientCertFile, clientKeyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	testutil.WriteKeyPair(t, clientCertFile, clientKeyFile, 100)
+	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	require.NoError(t, err)
+
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	startServiceWith(t, &xds.Config{Config: &configv1.Config{
+		Host:      "unix://" + socket,
+		Resources: runtimeResources(t, "version", "v1"),
+		Tls: &configv1.TLS{
+			CertFile:          certFile,
+			KeyFile:           keyFile,
+			CaFile:            clientCertFile,
+			RequireClientCert: true,
+		},
+	}, Logger: logger})
+
+	// The client trusts the server certificates, before and after the rotation.
+	roots := x509.NewCertPool()
+	addCert(t, roots, certFile)
+	// served returns the serial number of the certificate served to a new connection.
+	served := func(certificates ...tls.Certificate) (int64, error) {
+		var serial int64
+		conn := dial(t, socket, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      roots,
+			ServerName:   "localhost",
+			Certificates: certificates,
+			MinVersion:   tls.VersionTLS12,
+			VerifyConnection: func(state tls.ConnectionState) error {
+				serial = state.PeerCertificates[0].SerialNumber.Int64()
+				return nil
+			},
+		})))
+		_, err := tryFetch(conn, resource.RuntimeType, "rtds")
+		return serial, err
+	}
+
+	serial, err := served(clientCert)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), serial)
+
+	// A client without a certificate is rejected.
+	_, err = served()
+	require.Error(t, err)
+
+	// The rotated certificate is served to the new connections.
+	testutil.WriteKeyPair(t, certFile, keyFile, 2)
+	addCert(t, roots, certFile)
+	require.Eventually(t, func() bool {
+		return logger.count("xds service certificates reloaded") > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		serial, err := served(clientCert)
+		return err == nil && serial == 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestFileSecretRotation(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "xds.sock")
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	testutil.WriteKeyPair(t, certFile, keyFile, 1)
+
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	startServiceWith(t, &xds.Config{Config: &configv1.Config{
+		Host: "unix://" + socket,
+		Resources: &configv1.Resources{
+			FileSecrets: []*configv1.FileSecret{{Name: "server", CertFile: certFile, KeyFile: keyFile}},
+		},
+	}, Logger: logger})
+
+	conn := dial(t, socket)
+	res := fetch(t, conn, resource.SecretType, "server")
+	require.Equal(t, int64(1), secretSerial(t, res))
+
+	// Rotating the files produces a new snapshot, serving the updated secret with a bumped version.
+	testutil.WriteKeyPair(t, certFile, keyFile, 2)
+	require.Eventually(t, func() bool {
+		return logger.count("xds resource files reloaded") > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	var rotated *discoveryservice.DiscoveryResponse
+	require.Eventually(t, func() bool {
+		rotated = fetch(t, conn, resource.SecretType, "server")
+		return secretSerial(t, rotated) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotEqual(t, res.VersionInfo, rotated.VersionInfo)
+}
+
+// secretSerial returns the serial number of the certificate of the served TLS certificate secret.
+func secretSerial(t *testing.T, res *discoveryservice.DiscoveryResponse) int64 {
+	require.Len(t, res.Resources, 1)
+	var secret tlsv3.Secret
+	require.NoError(t, res.Resources[0].UnmarshalTo(&secret))
+	require.Equal(t, "server", secret.Name)
+	block, _ := pem.Decode(secret.GetTlsCertificate().GetCertificateChain().GetInlineBytes())
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert.SerialNumber.Int64()
+}
+
+func addCert(t *testing.T, pool *x509.CertPool, certFile string) {
+	b, err := os.ReadFile(certFile) //nolint:gosec
+	require.NoError(t, err)
+	require.True(t, pool.AppendCertsFromPEM(b))
+}
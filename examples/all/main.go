@@ -35,8 +35,13 @@ func main() {
 		xDS             = xds.New(g, &xds.Config{Logger: g.Logger})
 		authServer      = auth.New(g, &auth.Config{Logger: g.Logger})
 		ratelimitServer = ratelimit.New(g, &ratelimit.Config{Logger: g.Logger})
-		proxyServer     = proxy.New(g, &proxy.Config{Logger: g.Logger})
-		signalHandler   = new(runsignal.Handler)
+		proxyServer     = proxy.New(g, &proxy.Config{
+			Logger:    g.Logger,
+			XDS:       xDS,
+			Auth:      authServer,
+			RateLimit: ratelimitServer,
+		})
+		signalHandler = new(runsignal.Handler)
 	)
 	g.Register(xDS, authServer, ratelimitServer, proxyServer, signalHandler)
 	if err := g.Run(); err != nil {
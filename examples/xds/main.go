@@ -20,7 +20,6 @@ import (
 	"os"
 
 	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
-	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3" // allow to resolve envoy.extensions.upstreams.http.v3.*
 	"github.com/tetratelabs/run"
 	runsignal "github.com/tetratelabs/run/pkg/signal"
 	"github.com/tetratelabs/telemetry"
@@ -35,13 +34,16 @@ import (
 //go:embed proxy.yaml
 var configYAML []byte
 
+//go:embed xds.yaml
+var xdsConfigYAML []byte
+
 func main() {
 	var (
 		logger            = telemetry.NoopLogger()
 		g                 = &run.Group{Name: "example", Logger: logger}
-		xdsServerConfig   = &configv1.Config{Host: "localhost", Port: 8080}
+		xdsServerConfig   = mustLoadXDSConfig()
 		xdsServer         = xds.New(g, &xds.Config{Logger: g.Logger, Config: xdsServerConfig})
-		proxyServerConfig = &proxy.Config{Logger: g.Logger, GenerateConfig: generate}
+		proxyServerConfig = &proxy.Config{Logger: g.Logger, GenerateConfig: generate, XDS: xdsServer}
 		proxyServer       = proxy.New(g, proxyServerConfig)
 		signalHandler     = new(runsignal.Handler)
 	)
@@ -64,3 +66,15 @@ func generate() (*bootstrapv3.Bootstrap, error) {
 	}
 	return &config, nil
 }
+
+func mustLoadXDSConfig() *configv1.Config {
+	j, err := yaml.YAMLToJSON(xdsConfigYAML)
+	if err != nil {
+		panic(err)
+	}
+	var config configv1.Config
+	if err = protojson.Unmarshal(j, &config); err != nil {
+		panic(err)
+	}
+	return &config
+}
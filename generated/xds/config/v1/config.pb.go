@@ -23,6 +23,8 @@ package v1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -34,34 +36,644 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// DiscoveryService is a discovery service that can be registered to the server.
+type DiscoveryService int32
+
+const (
+	DiscoveryService_DISCOVERY_SERVICE_UNSPECIFIED DiscoveryService = 0
+	// Aggregated Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_ADS DiscoveryService = 1
+	// Cluster Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_CDS DiscoveryService = 2
+	// Endpoint Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_EDS DiscoveryService = 3
+	// Listener Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_LDS DiscoveryService = 4
+	// Route Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_RDS DiscoveryService = 5
+	// Secret Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_SDS DiscoveryService = 6
+	// Runtime Discovery Service.
+	DiscoveryService_DISCOVERY_SERVICE_RTDS DiscoveryService = 7
+)
+
+// Enum value maps for DiscoveryService.
+var (
+	DiscoveryService_name = map[int32]string{
+		0: "DISCOVERY_SERVICE_UNSPECIFIED",
+		1: "DISCOVERY_SERVICE_ADS",
+		2: "DISCOVERY_SERVICE_CDS",
+		3: "DISCOVERY_SERVICE_EDS",
+		4: "DISCOVERY_SERVICE_LDS",
+		5: "DISCOVERY_SERVICE_RDS",
+		6: "DISCOVERY_SERVICE_SDS",
+		7: "DISCOVERY_SERVICE_RTDS",
+	}
+	DiscoveryService_value = map[string]int32{
+		"DISCOVERY_SERVICE_UNSPECIFIED": 0,
+		"DISCOVERY_SERVICE_ADS":         1,
+		"DISCOVERY_SERVICE_CDS":         2,
+		"DISCOVERY_SERVICE_EDS":         3,
+		"DISCOVERY_SERVICE_LDS":         4,
+		"DISCOVERY_SERVICE_RDS":         5,
+		"DISCOVERY_SERVICE_SDS":         6,
+		"DISCOVERY_SERVICE_RTDS":        7,
+	}
+)
+
+func (x DiscoveryService) Enum() *DiscoveryService {
+	p := new(DiscoveryService)
+	*p = x
+	return p
+}
+
+func (x DiscoveryService) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiscoveryService) Descriptor() protoreflect.EnumDescriptor {
+	return file_xds_config_v1_config_proto_enumTypes[0].Descriptor()
+}
+
+func (DiscoveryService) Type() protoreflect.EnumType {
+	return &file_xds_config_v1_config_proto_enumTypes[0]
+}
+
+func (x DiscoveryService) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiscoveryService.Descriptor instead.
+func (DiscoveryService) EnumDescriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{0}
+}
+
 type Config struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Server host.
-	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	// Server port.
-	Port int32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// Server host. This can be a unix domain socket address, e.g. unix:///var/run/rundown/xds.sock,
+	// in that case the port is ignored.
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// Server port.
+	Port int32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// Resources to be served by the xDS server to the nodes that match no node group.
+	Resources *Resources `protobuf:"bytes,3,opt,name=resources,proto3" json:"resources,omitempty"`
+	// Node groups, each group is served with its own resources. A node is served by the first group
+	// it matches.
+	NodeGroups []*NodeGroup `protobuf:"bytes,4,rep,name=node_groups,json=nodeGroups,proto3" json:"node_groups,omitempty"`
+	// When true, the snapshot cache runs in ADS mode, i.e. it responds only when all the requested
+	// resources of a type are available. Enable this when the nodes are bootstrapped to use ADS.
+	Ads bool `protobuf:"varint,5,opt,name=ads,proto3" json:"ads,omitempty"`
+	// When true, incremental (delta) xDS streams are accepted. Otherwise, only state-of-the-world
+	// streams are served.
+	Delta bool `protobuf:"varint,6,opt,name=delta,proto3" json:"delta,omitempty"`
+	// The discovery services to be registered to the server. When empty, all of them are registered.
+	Services []DiscoveryService `protobuf:"varint,7,rep,packed,name=services,proto3,enum=xds.config.v1.DiscoveryService" json:"services,omitempty"`
+	// The admin HTTP server, exposing the status of the xDS server. Disabled when it is not set.
+	Admin *Admin `protobuf:"bytes,8,opt,name=admin,proto3" json:"admin,omitempty"`
+	// TLS settings of the xDS gRPC server. The server accepts plaintext connections when it is not
+	// set.
+	Tls *TLS `protobuf:"bytes,9,opt,name=tls,proto3" json:"tls,omitempty"`
+	// Permissions of the unix domain socket file in octal, e.g. "0660". Only relevant when the host is
+	// a unix domain socket address. Default: "0600".
+	SocketMode string `protobuf:"bytes,10,opt,name=socket_mode,json=socketMode,proto3" json:"socket_mode,omitempty"`
+	// Settings of the xDS gRPC server. The gRPC defaults are used when it is not set.
+	GrpcServer *GrpcServer `protobuf:"bytes,11,opt,name=grpc_server,json=grpcServer,proto3" json:"grpc_server,omitempty"`
+	// A directory of YAML or JSON files, each file holds an Envoy v3 API resource with its "@type",
+	// e.g. "@type: type.googleapis.com/envoy.config.cluster.v3.Cluster". The resources are served to
+	// the nodes that match no node group, in addition to the ones listed in resources. The snapshots
+	// are rebuilt when any file in the directory changes.
+	ResourcesDir string `protobuf:"bytes,12,opt,name=resources_dir,json=resourcesDir,proto3" json:"resources_dir,omitempty"`
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Config) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Config) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *Config) GetNodeGroups() []*NodeGroup {
+	if x != nil {
+		return x.NodeGroups
+	}
+	return nil
+}
+
+func (x *Config) GetAds() bool {
+	if x != nil {
+		return x.Ads
+	}
+	return false
+}
+
+func (x *Config) GetDelta() bool {
+	if x != nil {
+		return x.Delta
+	}
+	return false
+}
+
+func (x *Config) GetServices() []DiscoveryService {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *Config) GetAdmin() *Admin {
+	if x != nil {
+		return x.Admin
+	}
+	return nil
+}
+
+func (x *Config) GetTls() *TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Config) GetSocketMode() string {
+	if x != nil {
+		return x.SocketMode
+	}
+	return ""
+}
+
+func (x *Config) GetGrpcServer() *GrpcServer {
+	if x != nil {
+		return x.GrpcServer
+	}
+	return nil
+}
+
+func (x *Config) GetResourcesDir() string {
+	if x != nil {
+		return x.ResourcesDir
+	}
+	return ""
+}
+
+// GrpcServer is the gRPC server settings. An unset field means the gRPC default is used.
+type GrpcServer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The maximum number of concurrent streams per client connection. Since each xDS stream is a
+	// long-lived stream, this limits the number of subscriptions per client connection.
+	// Default: unlimited.
+	MaxConcurrentStreams uint32 `protobuf:"varint,1,opt,name=max_concurrent_streams,json=maxConcurrentStreams,proto3" json:"max_concurrent_streams,omitempty"`
+	// The maximum message size in bytes the server can receive.
+	// Default: 4194304 (4MiB).
+	MaxRecvMsgSize uint32 `protobuf:"varint,2,opt,name=max_recv_msg_size,json=maxRecvMsgSize,proto3" json:"max_recv_msg_size,omitempty"`
+	// The maximum message size in bytes the server can send.
+	// Default: 2147483647.
+	MaxSendMsgSize uint32 `protobuf:"varint,3,opt,name=max_send_msg_size,json=maxSendMsgSize,proto3" json:"max_send_msg_size,omitempty"`
+	// After a duration of this time, if the server doesn't see any activity it pings the client to
+	// see if the transport is still alive.
+	// Default: 2h.
+	KeepaliveTime *durationpb.Duration `protobuf:"bytes,4,opt,name=keepalive_time,json=keepaliveTime,proto3" json:"keepalive_time,omitempty"`
+	// After having pinged for keepalive check, the server waits for this duration and if no activity
+	// is seen even after that, the connection is closed.
+	// Default: 20s.
+	KeepaliveTimeout *durationpb.Duration `protobuf:"bytes,5,opt,name=keepalive_timeout,json=keepaliveTimeout,proto3" json:"keepalive_timeout,omitempty"`
+	// The minimum amount of time a client should wait before sending a keepalive ping. Clients that
+	// ping more often are disconnected.
+	// Default: 5m.
+	KeepaliveMinTime *durationpb.Duration `protobuf:"bytes,6,opt,name=keepalive_min_time,json=keepaliveMinTime,proto3" json:"keepalive_min_time,omitempty"`
+	// When true, clients are allowed to send keepalive pings even when there are no active streams.
+	// Default: false.
+	KeepalivePermitWithoutStream bool `protobuf:"varint,7,opt,name=keepalive_permit_without_stream,json=keepalivePermitWithoutStream,proto3" json:"keepalive_permit_without_stream,omitempty"`
+	// A duration for the amount of time after which an idle connection would be closed by sending a
+	// GoAway.
+	// Default: infinity.
+	MaxConnectionIdle *durationpb.Duration `protobuf:"bytes,8,opt,name=max_connection_idle,json=maxConnectionIdle,proto3" json:"max_connection_idle,omitempty"`
+	// A duration for the maximum amount of time a connection may exist before it will be closed by
+	// sending a GoAway. A random jitter of +/-10% will be added to spread out connection storms.
+	// Default: infinity.
+	MaxConnectionAge *durationpb.Duration `protobuf:"bytes,9,opt,name=max_connection_age,json=maxConnectionAge,proto3" json:"max_connection_age,omitempty"`
+	// An additive period after max_connection_age after which the connection will be forcibly closed.
+	// Default: infinity.
+	MaxConnectionAgeGrace *durationpb.Duration `protobuf:"bytes,10,opt,name=max_connection_age_grace,json=maxConnectionAgeGrace,proto3" json:"max_connection_age_grace,omitempty"`
+}
+
+func (x *GrpcServer) Reset() {
+	*x = GrpcServer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrpcServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrpcServer) ProtoMessage() {}
+
+func (x *GrpcServer) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrpcServer.ProtoReflect.Descriptor instead.
+func (*GrpcServer) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GrpcServer) GetMaxConcurrentStreams() uint32 {
+	if x != nil {
+		return x.MaxConcurrentStreams
+	}
+	return 0
+}
+
+func (x *GrpcServer) GetMaxRecvMsgSize() uint32 {
+	if x != nil {
+		return x.MaxRecvMsgSize
+	}
+	return 0
+}
+
+func (x *GrpcServer) GetMaxSendMsgSize() uint32 {
+	if x != nil {
+		return x.MaxSendMsgSize
+	}
+	return 0
+}
+
+func (x *GrpcServer) GetKeepaliveTime() *durationpb.Duration {
+	if x != nil {
+		return x.KeepaliveTime
+	}
+	return nil
+}
+
+func (x *GrpcServer) GetKeepaliveTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.KeepaliveTimeout
+	}
+	return nil
+}
+
+func (x *GrpcServer) GetKeepaliveMinTime() *durationpb.Duration {
+	if x != nil {
+		return x.KeepaliveMinTime
+	}
+	return nil
+}
+
+func (x *GrpcServer) GetKeepalivePermitWithoutStream() bool {
+	if x != nil {
+		return x.KeepalivePermitWithoutStream
+	}
+	return false
+}
+
+func (x *GrpcServer) GetMaxConnectionIdle() *durationpb.Duration {
+	if x != nil {
+		return x.MaxConnectionIdle
+	}
+	return nil
+}
+
+func (x *GrpcServer) GetMaxConnectionAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxConnectionAge
+	}
+	return nil
+}
+
+func (x *GrpcServer) GetMaxConnectionAgeGrace() *durationpb.Duration {
+	if x != nil {
+		return x.MaxConnectionAgeGrace
+	}
+	return nil
+}
+
+// TLS is the TLS settings of a server. The certificates are reloaded when the files change.
+type TLS struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path to the PEM-encoded server certificate chain. Required.
+	CertFile string `protobuf:"bytes,1,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	// Path to the PEM-encoded server private key. Required.
+	KeyFile string `protobuf:"bytes,2,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// Path to the PEM-encoded CA certificates to verify client certificates.
+	CaFile string `protobuf:"bytes,3,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// When true, clients are required to present a certificate verified by the CA certificates in
+	// ca_file (mutual TLS).
+	RequireClientCert bool `protobuf:"varint,4,opt,name=require_client_cert,json=requireClientCert,proto3" json:"require_client_cert,omitempty"`
+}
+
+func (x *TLS) Reset() {
+	*x = TLS{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLS) ProtoMessage() {}
+
+func (x *TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLS.ProtoReflect.Descriptor instead.
+func (*TLS) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TLS) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *TLS) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *TLS) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *TLS) GetRequireClientCert() bool {
+	if x != nil {
+		return x.RequireClientCert
+	}
+	return false
+}
+
+// Admin is the admin HTTP server settings. The server exposes:
+//   - /status: the connected nodes with their ACKed and NACKed versions, and the snapshot versions
+//     served per resource type for each node group.
+//   - /snapshots: the dump of the current snapshot resources for each node group.
+//   - /runtime: the runtime overrides on GET. On POST, when allowed, sets the runtime keys of a layer
+//     from the JSON object in the request body, e.g. POST /runtime?node_group=default&layer=rtds
+//     with {"envoy.reloadable_features.foo": false}. A null value removes the override of the key.
+//
+// Since the server is not authenticated, it is recommended to listen on a loopback address or a unix
+// domain socket.
+type Admin struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Server host.
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// Server port.
+	Port int32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// Allow setting the runtime overrides through POST /runtime. Since the server is not
+	// authenticated, this is disabled by default.
+	AllowRuntimeUpdates bool `protobuf:"varint,3,opt,name=allow_runtime_updates,json=allowRuntimeUpdates,proto3" json:"allow_runtime_updates,omitempty"`
+}
+
+func (x *Admin) Reset() {
+	*x = Admin{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Admin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Admin) ProtoMessage() {}
+
+func (x *Admin) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Admin.ProtoReflect.Descriptor instead.
+func (*Admin) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Admin) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Admin) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Admin) GetAllowRuntimeUpdates() bool {
+	if x != nil {
+		return x.AllowRuntimeUpdates
+	}
+	return false
+}
+
+// NodeGroup is a group of nodes that are served with the same resources.
+type NodeGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the group. It is required to be unique, and can not be "default", since it is reserved
+	// for the nodes that match no group.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The criteria for a node to be a member of this group.
+	Match *NodeMatch `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
+	// Resources to be served to the members of this group.
+	Resources *Resources `protobuf:"bytes,3,opt,name=resources,proto3" json:"resources,omitempty"`
+	// A directory of resource files to be served to the members of this group, in addition to the
+	// ones listed in resources. See: Config.resources_dir.
+	ResourcesDir string `protobuf:"bytes,4,opt,name=resources_dir,json=resourcesDir,proto3" json:"resources_dir,omitempty"`
+}
+
+func (x *NodeGroup) Reset() {
+	*x = NodeGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGroup) ProtoMessage() {}
+
+func (x *NodeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGroup.ProtoReflect.Descriptor instead.
+func (*NodeGroup) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NodeGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NodeGroup) GetMatch() *NodeMatch {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *NodeGroup) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *NodeGroup) GetResourcesDir() string {
+	if x != nil {
+		return x.ResourcesDir
+	}
+	return ""
+}
+
+// NodeMatch specifies the criteria to match a node. All the specified criteria are required to
+// match, while an empty criterion matches any node.
+type NodeMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Matches when the node ID is one of these.
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// Matches when the node cluster is one of these.
+	Clusters []string `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	// Matches when the node metadata has all of these keys, with the exact string values.
+	Metadata map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *Config) Reset() {
-	*x = Config{}
+func (x *NodeMatch) Reset() {
+	*x = NodeMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xds_config_v1_config_proto_msgTypes[0]
+		mi := &file_xds_config_v1_config_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Config) String() string {
+func (x *NodeMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Config) ProtoMessage() {}
+func (*NodeMatch) ProtoMessage() {}
 
-func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_xds_config_v1_config_proto_msgTypes[0]
+func (x *NodeMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -72,23 +684,295 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Config.ProtoReflect.Descriptor instead.
-func (*Config) Descriptor() ([]byte, []int) {
-	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use NodeMatch.ProtoReflect.Descriptor instead.
+func (*NodeMatch) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Config) GetHost() string {
+func (x *NodeMatch) GetIds() []string {
 	if x != nil {
-		return x.Host
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *NodeMatch) GetClusters() []string {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *NodeMatch) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Resources holds the xDS resources to be served as a snapshot. Each entry is written as the
+// YAML/JSON representation of the corresponding Envoy v3 API message. The resources are required
+// to be consistent, e.g. each EDS cluster must have its load assignment listed in endpoints, and
+// each listener using RDS must have its route configuration listed in routes.
+type Resources struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// List of envoy.config.cluster.v3.Cluster.
+	Clusters []*structpb.Struct `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	// List of envoy.config.endpoint.v3.ClusterLoadAssignment.
+	Endpoints []*structpb.Struct `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	// List of envoy.config.route.v3.RouteConfiguration.
+	Routes []*structpb.Struct `protobuf:"bytes,3,rep,name=routes,proto3" json:"routes,omitempty"`
+	// List of envoy.config.listener.v3.Listener.
+	Listeners []*structpb.Struct `protobuf:"bytes,4,rep,name=listeners,proto3" json:"listeners,omitempty"`
+	// List of envoy.extensions.transport_sockets.tls.v3.Secret.
+	Secrets []*structpb.Struct `protobuf:"bytes,5,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// Secrets loaded from PEM-encoded files, served as envoy.extensions.transport_sockets.tls.v3.Secret.
+	// The snapshots are rebuilt when the files change, e.g. when the certificates are rotated.
+	FileSecrets []*FileSecret `protobuf:"bytes,6,rep,name=file_secrets,json=fileSecrets,proto3" json:"file_secrets,omitempty"`
+	// Runtime layers, served as envoy.service.runtime.v3.Runtime. A node fetches a layer through the
+	// RTDS layer with the same name in its bootstrap layered_runtime.
+	RuntimeLayers []*RuntimeLayer `protobuf:"bytes,7,rep,name=runtime_layers,json=runtimeLayers,proto3" json:"runtime_layers,omitempty"`
+}
+
+func (x *Resources) Reset() {
+	*x = Resources{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Resources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resources) ProtoMessage() {}
+
+func (x *Resources) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resources.ProtoReflect.Descriptor instead.
+func (*Resources) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Resources) GetClusters() []*structpb.Struct {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *Resources) GetEndpoints() []*structpb.Struct {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *Resources) GetRoutes() []*structpb.Struct {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *Resources) GetListeners() []*structpb.Struct {
+	if x != nil {
+		return x.Listeners
+	}
+	return nil
+}
+
+func (x *Resources) GetSecrets() []*structpb.Struct {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *Resources) GetFileSecrets() []*FileSecret {
+	if x != nil {
+		return x.FileSecrets
+	}
+	return nil
+}
+
+func (x *Resources) GetRuntimeLayers() []*RuntimeLayer {
+	if x != nil {
+		return x.RuntimeLayers
+	}
+	return nil
+}
+
+// RuntimeLayer is a named runtime layer.
+type RuntimeLayer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the layer. Required, and unique.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The runtime values keyed by the runtime keys, e.g. "envoy.reloadable_features.foo: false" or
+	// "upstream.use_http2: true".
+	Layer *structpb.Struct `protobuf:"bytes,2,opt,name=layer,proto3" json:"layer,omitempty"`
+}
+
+func (x *RuntimeLayer) Reset() {
+	*x = RuntimeLayer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeLayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeLayer) ProtoMessage() {}
+
+func (x *RuntimeLayer) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeLayer.ProtoReflect.Descriptor instead.
+func (*RuntimeLayer) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RuntimeLayer) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Config) GetPort() int32 {
+func (x *RuntimeLayer) GetLayer() *structpb.Struct {
 	if x != nil {
-		return x.Port
+		return x.Layer
 	}
-	return 0
+	return nil
+}
+
+// FileSecret is a secret loaded from files. It is served as a TlsCertificate when a certificate and
+// a key are given, otherwise as a ValidationContext when only CA certificates are given.
+type FileSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the secret, as referred by the SDS secret configs. Required.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Path to the certificate chain.
+	CertFile string `protobuf:"bytes,2,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	// Path to the private key.
+	KeyFile string `protobuf:"bytes,3,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// Path to the trusted CA certificates.
+	CaFile string `protobuf:"bytes,4,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// A directory holding "tls.crt" and "tls.key", or only "ca.crt", e.g. a mounted Kubernetes TLS
+	// secret. This can't be set together with the files above.
+	Dir string `protobuf:"bytes,5,opt,name=dir,proto3" json:"dir,omitempty"`
+	// Refer to the files by their paths instead of inlining their content, hence the key material is
+	// not sent to the nodes. This requires the nodes to be able to read the same paths, e.g. the nodes
+	// run on the same host.
+	ByPath bool `protobuf:"varint,6,opt,name=by_path,json=byPath,proto3" json:"by_path,omitempty"`
+}
+
+func (x *FileSecret) Reset() {
+	*x = FileSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xds_config_v1_config_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileSecret) ProtoMessage() {}
+
+func (x *FileSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_xds_config_v1_config_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileSecret.ProtoReflect.Descriptor instead.
+func (*FileSecret) Descriptor() ([]byte, []int) {
+	return file_xds_config_v1_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FileSecret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileSecret) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *FileSecret) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *FileSecret) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *FileSecret) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *FileSecret) GetByPath() bool {
+	if x != nil {
+		return x.ByPath
+	}
+	return false
 }
 
 var File_xds_config_v1_config_proto protoreflect.FileDescriptor
@@ -96,14 +980,180 @@ var File_xds_config_v1_config_proto protoreflect.FileDescriptor
 var file_xds_config_v1_config_proto_rawDesc = []byte{
 	0x0a, 0x1a, 0x78, 0x64, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x76, 0x31, 0x2f,
 	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x78, 0x64,
-	0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x22, 0x30, 0x0a, 0x06, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x30, 0x5a,
-	0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x69, 0x6f, 0x2f,
-	0x72, 0x75, 0x6e, 0x64, 0x6f, 0x77, 0x6e, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
-	0x64, 0x2f, 0x78, 0x64, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x76, 0x31, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdc, 0x03, 0x0a, 0x06, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x36, 0x0a, 0x09,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x78, 0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x78, 0x64, 0x73, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x52, 0x0a, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x61, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x3b, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x78, 0x64, 0x73, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76,
+	0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x78, 0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x12, 0x24, 0x0a, 0x03, 0x74, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x78, 0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x4c,
+	0x53, 0x52, 0x03, 0x74, 0x6c, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f, 0x63,
+	0x6b, 0x65, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x67, 0x72, 0x70, 0x63, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x78,
+	0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x70,
+	0x63, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x67, 0x72, 0x70, 0x63, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x5f, 0x64, 0x69, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x44, 0x69, 0x72, 0x22, 0x9a, 0x05, 0x0a, 0x0a, 0x47, 0x72, 0x70,
+	0x63, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x16, 0x6d, 0x61, 0x78, 0x5f, 0x63,
+	0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x12, 0x29, 0x0a,
+	0x11, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6d, 0x73, 0x67, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x63,
+	0x76, 0x4d, 0x73, 0x67, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x29, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6d, 0x73, 0x67, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x40, 0x0a, 0x0e, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x46, 0x0a, 0x11, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69,
+	0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x6b, 0x65, 0x65,
+	0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x47, 0x0a,
+	0x12, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x4d,
+	0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x45, 0x0a, 0x1f, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c,
+	0x69, 0x76, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x6f,
+	0x75, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x1c, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x74,
+	0x57, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x49, 0x0a,
+	0x13, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x6c, 0x65, 0x12, 0x47, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x5f,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x10, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x67,
+	0x65, 0x12, 0x52, 0x0a, 0x18, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x61, 0x67, 0x65, 0x5f, 0x67, 0x72, 0x61, 0x63, 0x65, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15,
+	0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x67, 0x65,
+	0x47, 0x72, 0x61, 0x63, 0x65, 0x22, 0x86, 0x01, 0x0a, 0x03, 0x54, 0x4c, 0x53, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65,
+	0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65,
+	0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2e,
+	0x0a, 0x13, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x63, 0x65, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x22, 0x63,
+	0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x32, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x78, 0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x78, 0x64, 0x73, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x44,
+	0x69, 0x72, 0x22, 0xba, 0x01, 0x0a, 0x09, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69,
+	0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x12, 0x42,
+	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x26, 0x2e, 0x78, 0x64, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x94, 0x03, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x33, 0x0a,
+	0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x09,
+	0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x6c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x73, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x0c, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x78, 0x64, 0x73,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x73, 0x12, 0x42, 0x0a, 0x0e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x78, 0x64, 0x73,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x0d, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x22, 0x51, 0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x52, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0x9c, 0x01, 0x0a, 0x0a, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x63, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65, 0x79,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x64, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x72, 0x12,
+	0x17, 0x0a, 0x07, 0x62, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x62, 0x79, 0x50, 0x61, 0x74, 0x68, 0x2a, 0xf3, 0x01, 0x0a, 0x10, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x21, 0x0a,
+	0x1d, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49,
+	0x43, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x19, 0x0a, 0x15, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x45,
+	0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x41, 0x44, 0x53, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x44,
+	0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45,
+	0x5f, 0x43, 0x44, 0x53, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56,
+	0x45, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x45, 0x44, 0x53, 0x10,
+	0x03, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53,
+	0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4c, 0x44, 0x53, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15,
+	0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43,
+	0x45, 0x5f, 0x52, 0x44, 0x53, 0x10, 0x05, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x49, 0x53, 0x43, 0x4f,
+	0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x53, 0x44, 0x53,
+	0x10, 0x06, 0x12, 0x1a, 0x0a, 0x16, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x5f,
+	0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x54, 0x44, 0x53, 0x10, 0x07, 0x42, 0x30,
+	0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x69, 0x6f,
+	0x2f, 0x72, 0x75, 0x6e, 0x64, 0x6f, 0x77, 0x6e, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x65, 0x64, 0x2f, 0x78, 0x64, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x76, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -118,16 +1168,52 @@ func file_xds_config_v1_config_proto_rawDescGZIP() []byte {
 	return file_xds_config_v1_config_proto_rawDescData
 }
 
-var file_xds_config_v1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_xds_config_v1_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_xds_config_v1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_xds_config_v1_config_proto_goTypes = []interface{}{
-	(*Config)(nil), // 0: xds.config.v1.Config
+	(DiscoveryService)(0),       // 0: xds.config.v1.DiscoveryService
+	(*Config)(nil),              // 1: xds.config.v1.Config
+	(*GrpcServer)(nil),          // 2: xds.config.v1.GrpcServer
+	(*TLS)(nil),                 // 3: xds.config.v1.TLS
+	(*Admin)(nil),               // 4: xds.config.v1.Admin
+	(*NodeGroup)(nil),           // 5: xds.config.v1.NodeGroup
+	(*NodeMatch)(nil),           // 6: xds.config.v1.NodeMatch
+	(*Resources)(nil),           // 7: xds.config.v1.Resources
+	(*RuntimeLayer)(nil),        // 8: xds.config.v1.RuntimeLayer
+	(*FileSecret)(nil),          // 9: xds.config.v1.FileSecret
+	nil,                         // 10: xds.config.v1.NodeMatch.MetadataEntry
+	(*durationpb.Duration)(nil), // 11: google.protobuf.Duration
+	(*structpb.Struct)(nil),     // 12: google.protobuf.Struct
 }
 var file_xds_config_v1_config_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7,  // 0: xds.config.v1.Config.resources:type_name -> xds.config.v1.Resources
+	5,  // 1: xds.config.v1.Config.node_groups:type_name -> xds.config.v1.NodeGroup
+	0,  // 2: xds.config.v1.Config.services:type_name -> xds.config.v1.DiscoveryService
+	4,  // 3: xds.config.v1.Config.admin:type_name -> xds.config.v1.Admin
+	3,  // 4: xds.config.v1.Config.tls:type_name -> xds.config.v1.TLS
+	2,  // 5: xds.config.v1.Config.grpc_server:type_name -> xds.config.v1.GrpcServer
+	11, // 6: xds.config.v1.GrpcServer.keepalive_time:type_name -> google.protobuf.Duration
+	11, // 7: xds.config.v1.GrpcServer.keepalive_timeout:type_name -> google.protobuf.Duration
+	11, // 8: xds.config.v1.GrpcServer.keepalive_min_time:type_name -> google.protobuf.Duration
+	11, // 9: xds.config.v1.GrpcServer.max_connection_idle:type_name -> google.protobuf.Duration
+	11, // 10: xds.config.v1.GrpcServer.max_connection_age:type_name -> google.protobuf.Duration
+	11, // 11: xds.config.v1.GrpcServer.max_connection_age_grace:type_name -> google.protobuf.Duration
+	6,  // 12: xds.config.v1.NodeGroup.match:type_name -> xds.config.v1.NodeMatch
+	7,  // 13: xds.config.v1.NodeGroup.resources:type_name -> xds.config.v1.Resources
+	10, // 14: xds.config.v1.NodeMatch.metadata:type_name -> xds.config.v1.NodeMatch.MetadataEntry
+	12, // 15: xds.config.v1.Resources.clusters:type_name -> google.protobuf.Struct
+	12, // 16: xds.config.v1.Resources.endpoints:type_name -> google.protobuf.Struct
+	12, // 17: xds.config.v1.Resources.routes:type_name -> google.protobuf.Struct
+	12, // 18: xds.config.v1.Resources.listeners:type_name -> google.protobuf.Struct
+	12, // 19: xds.config.v1.Resources.secrets:type_name -> google.protobuf.Struct
+	9,  // 20: xds.config.v1.Resources.file_secrets:type_name -> xds.config.v1.FileSecret
+	8,  // 21: xds.config.v1.Resources.runtime_layers:type_name -> xds.config.v1.RuntimeLayer
+	12, // 22: xds.config.v1.RuntimeLayer.layer:type_name -> google.protobuf.Struct
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_xds_config_v1_config_proto_init() }
@@ -148,19 +1234,116 @@ func file_xds_config_v1_config_proto_init() {
 				return nil
 			}
 		}
+		file_xds_config_v1_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrpcServer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TLS); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Admin); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Resources); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeLayer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xds_config_v1_config_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_xds_config_v1_config_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_xds_config_v1_config_proto_goTypes,
 		DependencyIndexes: file_xds_config_v1_config_proto_depIdxs,
+		EnumInfos:         file_xds_config_v1_config_proto_enumTypes,
 		MessageInfos:      file_xds_config_v1_config_proto_msgTypes,
 	}.Build()
 	File_xds_config_v1_config_proto = out.File
@@ -5,14 +5,20 @@ go 1.17
 require (
 	github.com/bazelbuild/bazelisk v1.11.0
 	github.com/codeclysm/extract v2.2.0+incompatible
+	github.com/coocood/freecache v1.1.0
 	github.com/envoyproxy/go-control-plane v0.10.2-0.20220128233943-cf8dcaf571d7
 	github.com/envoyproxy/protoc-gen-validate v0.6.3
 	github.com/envoyproxy/ratelimit v1.4.1-0.20220124185553-8d6488ead861
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/iancoleman/strcase v0.2.0
+	github.com/lyft/gostats v0.4.0
+	github.com/sirupsen/logrus v1.6.0
 	github.com/stretchr/testify v1.7.0
 	github.com/tetratelabs/run v0.1.2
 	github.com/tetratelabs/telemetry v0.7.1
 	github.com/ulikunitz/xz v0.5.10
+	golang.org/x/net v0.0.0-20210813160813-60bc85c4be6d
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
 	google.golang.org/grpc v1.36.0
 	google.golang.org/protobuf v1.27.1
 	sigs.k8s.io/yaml v1.3.0
@@ -23,9 +29,7 @@ require (
 	github.com/census-instrumentation/opencensus-proto v0.2.1 // indirect
 	github.com/cespare/xxhash v1.1.0 // indirect
 	github.com/cncf/xds/go v0.0.0-20211001041855-01bcc9b48dfe // indirect
-	github.com/coocood/freecache v1.1.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/fsnotify/fsnotify v1.4.7 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/gorilla/mux v1.7.4-0.20191121170500-49c01487a141 // indirect
 	github.com/h2non/filetype v1.1.3 // indirect
@@ -36,18 +40,14 @@ require (
 	github.com/konsorten/go-windows-terminal-sequences v1.0.3 // indirect
 	github.com/logrusorgru/aurora v2.0.3+incompatible // indirect
 	github.com/lyft/goruntime v0.2.5 // indirect
-	github.com/lyft/gostats v0.4.0 // indirect
 	github.com/mediocregopher/radix/v3 v3.5.1 // indirect
 	github.com/oklog/run v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/sirupsen/logrus v1.6.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/tetratelabs/multierror v1.1.0 // indirect
-	golang.org/x/net v0.0.0-20210813160813-60bc85c4be6d // indirect
 	golang.org/x/sys v0.0.0-20210816183151-1e6c022a8912 // indirect
 	golang.org/x/text v0.3.7 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
-	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
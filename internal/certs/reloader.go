@@ -0,0 +1,121 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Reloader holds server certificates loaded from files, which can be reloaded when the files
+// change. The TLS config returned by TLSConfig always uses the last loaded certificates.
+type Reloader struct {
+	certFile          string
+	keyFile           string
+	caFile            string
+	requireClientCert bool
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// NewReloader returns a new Reloader with the certificates loaded from the given files. The caFile
+// is optional, unless requireClientCert is true.
+func NewReloader(certFile, keyFile, caFile string, requireClientCert bool) (*Reloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both cert file and key file are required")
+	}
+	if requireClientCert && caFile == "" {
+		return nil, errors.New("ca file is required to verify client certificates")
+	}
+	r := &Reloader{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		caFile:            caFile,
+		requireClientCert: requireClientCert,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificates from the files. When it fails, the previously loaded certificates
+// are kept.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		b, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return fmt.Errorf("failed to load ca certificates from %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.pool = pool
+	return nil
+}
+
+// Paths returns the paths of the loaded files.
+func (r *Reloader) Paths() []string {
+	paths := []string{r.certFile, r.keyFile}
+	if r.caFile != "" {
+		paths = append(paths, r.caFile)
+	}
+	return paths
+}
+
+// TLSConfig returns a server TLS config that uses the last loaded certificates on each handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.configForClient,
+	}
+}
+
+func (r *Reloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+		NextProtos:   []string{"h2"}, // Required by gRPC.
+	}
+	if r.pool != nil {
+		config.ClientCAs = r.pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return config, nil
+}
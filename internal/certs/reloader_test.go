@@ -0,0 +1,63 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certs_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/certs"
+	"github.com/dio/rundown/internal/testutil"
+)
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	testutil.WriteKeyPair(t, certFile, keyFile, 1)
+	_, err := certs.NewReloader(certFile, keyFile, "", true)
+	require.Error(t, err) // Requiring client certs without CA.
+
+	r, err := certs.NewReloader(certFile, keyFile, certFile, true)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), serialOf(t, r))
+
+	config, err := r.TLSConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+
+	testutil.WriteKeyPair(t, certFile, keyFile, 2)
+	require.NoError(t, r.Reload())
+	require.Equal(t, int64(2), serialOf(t, r))
+
+	// A broken key pair keeps the previously loaded certificates.
+	require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+	require.Error(t, r.Reload())
+	require.Equal(t, int64(2), serialOf(t, r))
+}
+
+func serialOf(t *testing.T, r *certs.Reloader) int64 {
+	config, err := r.TLSConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	return cert.SerialNumber.Int64()
+}
@@ -0,0 +1,297 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	adminv3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dio/rundown/internal/listener"
+)
+
+// Admin is a client of the Envoy admin interface.
+type Admin struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewAdmin returns a client of the admin interface configured in the admin block of the given
+// bootstrap. The admin address is either a socket address or a pipe.
+func NewAdmin(b *bootstrapv3.Bootstrap) (*Admin, error) {
+	address := b.GetAdmin().GetAddress()
+	if pipe := address.GetPipe(); pipe != nil {
+		dialer := &net.Dialer{}
+		return &Admin{
+			client: &http.Client{Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", pipe.Path)
+				},
+			}},
+			// The host is ignored, since the pipe is always dialed.
+			baseURL: "http://envoy",
+		}, nil
+	}
+	socket := address.GetSocketAddress()
+	if socket == nil || socket.GetPortValue() == 0 {
+		return nil, errors.New("no admin socket address or pipe with a fixed port")
+	}
+	return &Admin{
+		client:  &http.Client{},
+		baseURL: "http://" + listener.DialAddress(socket.Address, int32(socket.GetPortValue())),
+	}, nil
+}
+
+// DrainListeners drains the listeners. When graceful, the listeners are drained gradually during
+// the drain time, instead of being stopped right away.
+func (a *Admin) DrainListeners(ctx context.Context, graceful bool) error {
+	path := "/drain_listeners"
+	if graceful {
+		path += "?graceful"
+	}
+	_, err := a.do(ctx, http.MethodPost, path)
+	return err
+}
+
+// FailHealthcheck fails the health check filters, so downstream load balancers stop sending new
+// requests.
+func (a *Admin) FailHealthcheck(ctx context.Context) error {
+	_, err := a.do(ctx, http.MethodPost, "/healthcheck/fail")
+	return err
+}
+
+// Ready returns true when the server is LIVE. Otherwise, the returned state tells the reason, e.g.
+// PRE_INITIALIZING.
+func (a *Admin) Ready(ctx context.Context) (bool, string, error) {
+	res, err := a.send(ctx, http.MethodGet, "/ready")
+	if err != nil {
+		return false, "", err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, "", err
+	}
+	state := strings.TrimSpace(string(b))
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, state, nil
+	case http.StatusServiceUnavailable:
+		return false, state, nil
+	}
+	return false, "", fmt.Errorf("GET /ready: %s: %s", res.Status, state)
+}
+
+// ServerInfo returns the information of the server, e.g. its version, state and command line options.
+func (a *Admin) ServerInfo(ctx context.Context) (*adminv3.ServerInfo, error) {
+	var info adminv3.ServerInfo
+	if err := a.get(ctx, "/server_info", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Stats returns the counters and the gauges which names match the given regular expression. An
+// empty filter returns all of them. Histograms are not included.
+func (a *Admin) Stats(ctx context.Context, filter string) (map[string]uint64, error) {
+	path := "/stats"
+	if filter != "" {
+		path += "?filter=" + url.QueryEscape(filter)
+	}
+	b, err := a.do(ctx, http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		// Each line is in the form of: <name>: <value>. The value of a histogram is a list of
+		// percentiles, which is skipped.
+		name, value, ok := cut(scanner.Text(), ": ")
+		if !ok {
+			continue
+		}
+		if count, err := strconv.ParseUint(value, 10, 64); err == nil {
+			stats[name] = count
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// ActiveConnections returns the number of the active downstream connections of the listeners,
+// excluding the admin listener.
+func (a *Admin) ActiveConnections(ctx context.Context) (uint64, error) {
+	stats, err := a.Stats(ctx, `^listener\..*\.downstream_cx_active$`)
+	if err != nil {
+		return 0, err
+	}
+	var active uint64
+	for name, count := range stats {
+		if !strings.HasPrefix(name, "listener.admin.") {
+			active += count
+		}
+	}
+	return active, nil
+}
+
+// ConfigDump returns the current config of the server. When resource is set, e.g. dynamic_listeners,
+// only the resources of that type are dumped. A typed config of a type that is not linked into the
+// binary, e.g. of an extension, is kept with its type URL, but without its content.
+func (a *Admin) ConfigDump(ctx context.Context, resource string) (*adminv3.ConfigDump, error) {
+	path := "/config_dump"
+	if resource != "" {
+		path += "?resource=" + url.QueryEscape(resource)
+	}
+	var dump adminv3.ConfigDump
+	if err := a.get(ctx, path, &dump); err != nil {
+		return nil, err
+	}
+	return &dump, nil
+}
+
+// Clusters returns the upstream clusters, with the status of their hosts.
+func (a *Admin) Clusters(ctx context.Context) (*adminv3.Clusters, error) {
+	var clusters adminv3.Clusters
+	if err := a.get(ctx, "/clusters?format=json", &clusters); err != nil {
+		return nil, err
+	}
+	return &clusters, nil
+}
+
+// Logging returns the log levels of the active loggers, keyed by the logger names.
+func (a *Admin) Logging(ctx context.Context) (map[string]string, error) {
+	return a.logging(ctx, http.MethodGet, "/logging")
+}
+
+// SetLogging sets the log level of the given logger, and returns the updated log levels of the active
+// loggers. An empty logger sets the log level of all loggers.
+func (a *Admin) SetLogging(ctx context.Context, logger, level string) (map[string]string, error) {
+	if _, ok := logLevels[level]; !ok {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+	if logger == "" {
+		logger = "level"
+	}
+	return a.logging(ctx, http.MethodPost, "/logging?"+url.QueryEscape(logger)+"="+level)
+}
+
+// logging sends a logging request, and parses the returned log levels.
+func (a *Admin) logging(ctx context.Context, method, path string) (map[string]string, error) {
+	b, err := a.do(ctx, method, path)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		// The first line is "active loggers:", followed by lines in the form of: <name>: <level>.
+		name, level, ok := cut(strings.TrimSpace(scanner.Text()), ": ")
+		if ok {
+			levels[name] = level
+		}
+	}
+	return levels, scanner.Err()
+}
+
+// get sends a GET request to the given path, and unmarshals the returned JSON into the message.
+func (a *Admin) get(ctx context.Context, path string, m proto.Message) error {
+	b, err := a.do(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true, Resolver: tolerantResolver{protoregistry.GlobalTypes}}.
+		Unmarshal(b, m)
+}
+
+// tolerantResolver resolves the message types that are not linked into the binary to an empty
+// message type, hence their fields are discarded.
+type tolerantResolver struct {
+	*protoregistry.Types
+}
+
+func (r tolerantResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := r.Types.FindMessageByURL(url)
+	if errors.Is(err, protoregistry.NotFound) {
+		return unknownType, nil
+	}
+	return mt, err
+}
+
+// unknownType is the empty message type of the unresolved types.
+var unknownType = func() protoreflect.MessageType {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("rundown/envoy/unknown.proto"),
+		Package:     proto.String("rundown.envoy"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Unknown")}},
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return dynamicpb.NewMessageType(file.Messages().Get(0))
+}()
+
+// do sends a request to the given path, and returns the response body. A response other than 200 OK
+// is an error.
+func (a *Admin) do(ctx context.Context, method, path string) ([]byte, error) {
+	res, err := a.send(ctx, method, path)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(b)))
+	}
+	return b, nil
+}
+
+// send sends a request to the given path.
+func (a *Admin) send(ctx context.Context, method, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return a.client.Do(req)
+}
+
+// cut slices s around the first instance of sep, since strings.Cut requires go1.18.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
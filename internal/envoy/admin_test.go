@@ -0,0 +1,203 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	adminv3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/envoy"
+)
+
+func TestAdmin(t *testing.T) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.String())
+		if r.URL.Path == "/stats" {
+			_, _ = w.Write([]byte("listener.0.0.0.0_10000.downstream_cx_active: 2\n" +
+				"listener.0.0.0.0_10001.downstream_cx_active: 3\n" +
+				"listener.admin.downstream_cx_active: 1\n"))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	admin := newAdmin(t, server)
+
+	ctx := context.Background()
+	require.NoError(t, admin.DrainListeners(ctx, true))
+	require.NoError(t, admin.FailHealthcheck(ctx))
+	active, err := admin.ActiveConnections(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), active)
+	require.Equal(t, []string{
+		"POST /drain_listeners?graceful",
+		"POST /healthcheck/fail",
+		"GET /stats?filter=%5Elistener%5C..%2A%5C.downstream_cx_active%24",
+	}, requests)
+
+	// Through a pipe.
+	path := filepath.Join(t.TempDir(), "admin.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	pipeServer := &httptest.Server{Listener: l, Config: &http.Server{Handler: mux}} //nolint:gosec
+	pipeServer.Start()
+	defer pipeServer.Close()
+
+	admin, err = envoy.NewAdmin(&bootstrapv3.Bootstrap{Admin: &bootstrapv3.Admin{
+		Address: &corev3.Address{Address: &corev3.Address_Pipe{Pipe: &corev3.Pipe{Path: path}}},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, admin.DrainListeners(ctx, false))
+	require.Equal(t, "POST /drain_listeners", requests[len(requests)-1])
+
+	_, err = envoy.NewAdmin(&bootstrapv3.Bootstrap{})
+	require.Error(t, err)
+}
+
+func TestAdminTyped(t *testing.T) {
+	ready := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("PRE_INITIALIZING\n"))
+			return
+		}
+		_, _ = w.Write([]byte("LIVE\n"))
+	})
+	mux.HandleFunc("/server_info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.21.0","state":"LIVE","unknown":true}`))
+	})
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("cluster.a.upstream_cx_total: 3\n" +
+			"cluster.a.upstream_rq_time: P0(nan,1.0) P25(nan,1.025)\n"))
+	})
+	mux.HandleFunc("/clusters", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "json", r.URL.Query().Get("format"))
+		_, _ = w.Write([]byte(`{"cluster_statuses":[{"name":"a"}]}`))
+	})
+	mux.HandleFunc("/config_dump", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "dynamic_listeners", r.URL.Query().Get("resource"))
+		_, _ = w.Write([]byte(`{"configs":[]}`))
+	})
+	mux.HandleFunc("/logging", func(w http.ResponseWriter, r *http.Request) {
+		level := "info"
+		if r.Method == http.MethodPost {
+			level = r.URL.Query().Get("upstream")
+		}
+		_, _ = w.Write([]byte("active loggers:\n  admin: info\n  upstream: " + level + "\n"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	admin := newAdmin(t, server)
+
+	ctx := context.Background()
+	ok, state, err := admin.Ready(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "PRE_INITIALIZING", state)
+	ready = true
+	ok, state, err = admin.Ready(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "LIVE", state)
+
+	info, err := admin.ServerInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1.21.0", info.Version)
+
+	stats, err := admin.Stats(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"cluster.a.upstream_cx_total": 3}, stats)
+
+	clusters, err := admin.Clusters(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", clusters.ClusterStatuses[0].Name)
+
+	_, err = admin.ConfigDump(ctx, "dynamic_listeners")
+	require.NoError(t, err)
+
+	levels, err := admin.Logging(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"admin": "info", "upstream": "info"}, levels)
+	levels, err = admin.SetLogging(ctx, "upstream", "debug")
+	require.NoError(t, err)
+	require.Equal(t, "debug", levels["upstream"])
+	_, err = admin.SetLogging(ctx, "upstream", "loud")
+	require.Error(t, err)
+}
+
+func TestAdminConfigDump(t *testing.T) {
+	dump, err := os.ReadFile(filepath.Join("testdata", "config_dump.json"))
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(dump)
+	}))
+	defer server.Close()
+
+	// The typed configs of the extensions, e.g. the HttpConnectionManager, are not linked into this
+	// test binary.
+	parsed, err := newAdmin(t, server).ConfigDump(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, parsed.Configs, 4)
+
+	var bootstrap adminv3.BootstrapConfigDump
+	require.NoError(t, parsed.Configs[0].UnmarshalTo(&bootstrap))
+	require.Equal(t, uint32(9901), bootstrap.Bootstrap.Admin.Address.GetSocketAddress().GetPortValue())
+
+	var listeners adminv3.ListenersConfigDump
+	require.NoError(t, parsed.Configs[2].UnmarshalTo(&listeners))
+	var l listenerv3.Listener
+	require.NoError(t, listeners.DynamicListeners[0].ActiveState.Listener.UnmarshalTo(&l))
+	require.Equal(t, "listener_0", l.Name)
+	require.Equal(t, uint32(10000), l.Address.GetSocketAddress().GetPortValue())
+	// The unresolved typed config keeps its type URL.
+	require.Equal(t,
+		"type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+		l.FilterChains[0].Filters[0].GetTypedConfig().GetTypeUrl())
+
+	var routes adminv3.RoutesConfigDump
+	require.NoError(t, parsed.Configs[3].UnmarshalTo(&routes))
+	require.Len(t, routes.DynamicRouteConfigs, 1)
+}
+
+func newAdmin(t *testing.T, server *httptest.Server) *envoy.Admin {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	portValue, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	admin, err := envoy.NewAdmin(&bootstrapv3.Bootstrap{Admin: &bootstrapv3.Admin{
+		Address: &corev3.Address{Address: &corev3.Address_SocketAddress{SocketAddress: &corev3.SocketAddress{
+			Address:       host,
+			PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: uint32(portValue)},
+		}}},
+	}})
+	require.NoError(t, err)
+	return admin
+}
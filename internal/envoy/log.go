@@ -0,0 +1,46 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"regexp"
+
+	"github.com/dio/rundown/internal/output"
+)
+
+// logPattern matches a line in the Envoy default log format: "[%Y-%m-%d %T.%e][%t][%l][%n] [%g:%#] %v",
+// e.g. [2022-03-01 10:00:00.000][1][info][main] [source/server/server.cc:368] initializing epoch 0.
+var logPattern = regexp.MustCompile(`^\[[^\]]+\]\[\d+\]\[(\w+)\]\[([^\]]*)\] (?:\[([^\]]+)\] )?(.*)$`)
+
+// ParseLog parses a line in the Envoy default log format, and maps its level: trace and debug to
+// debug, info and warning to info, error and critical to error. The logger name and the source
+// location are returned as key values. A line in other format is returned as is, with the info level.
+func ParseLog(line string) (output.Level, string, []interface{}) {
+	m := logPattern.FindStringSubmatch(line)
+	if m == nil {
+		return output.Info, line, nil
+	}
+	kv := []interface{}{"level", m[1], "logger", m[2]}
+	if m[3] != "" {
+		kv = append(kv, "source", m[3])
+	}
+	switch m[1] {
+	case "trace", "debug":
+		return output.Debug, m[4], kv
+	case "error", "critical":
+		return output.Error, m[4], kv
+	}
+	return output.Info, m[4], kv
+}
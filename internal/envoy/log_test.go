@@ -0,0 +1,63 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/envoy"
+	"github.com/dio/rundown/internal/output"
+)
+
+func TestParseLog(t *testing.T) {
+	tests := []struct {
+		line  string
+		level output.Level
+		msg   string
+		kv    []interface{}
+	}{
+		{
+			line:  "[2022-03-01 10:00:00.000][1][info][main] [source/server/server.cc:368] initializing epoch 0",
+			level: output.Info,
+			msg:   "initializing epoch 0",
+			kv:    []interface{}{"level", "info", "logger", "main", "source", "source/server/server.cc:368"},
+		},
+		{
+			line:  "[2022-03-01 10:00:00.000][12][debug][upstream] [source/common/upstream/cds_api_helper.cc:30] cds: add 1 cluster(s)",
+			level: output.Debug,
+			msg:   "cds: add 1 cluster(s)",
+			kv:    []interface{}{"level", "debug", "logger", "upstream", "source", "source/common/upstream/cds_api_helper.cc:30"},
+		},
+		{
+			line:  "[2022-03-01 10:00:00.000][1][critical][main] error initializing configuration",
+			level: output.Error,
+			msg:   "error initializing configuration",
+			kv:    []interface{}{"level", "critical", "logger", "main"},
+		},
+		{
+			line:  "not an envoy log",
+			level: output.Info,
+			msg:   "not an envoy log",
+		},
+	}
+	for _, tc := range tests {
+		level, msg, kv := envoy.ParseLog(tc.line)
+		require.Equal(t, tc.level, level, tc.line)
+		require.Equal(t, tc.msg, msg, tc.line)
+		require.Equal(t, tc.kv, kv, tc.line)
+	}
+}
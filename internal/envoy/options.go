@@ -0,0 +1,164 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envoy provides helpers for running the Envoy binary.
+package envoy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/run"
+)
+
+// logLevels are the valid Envoy log levels.
+var logLevels = map[string]struct{}{
+	"trace": {}, "debug": {}, "info": {}, "warning": {}, "warn": {}, "error": {}, "critical": {}, "off": {},
+}
+
+// Options holds the Envoy command line options. The unset options are not passed, hence the Envoy
+// defaults are used.
+type Options struct {
+	LogLevel           string
+	ComponentLogLevel  string
+	Concurrency        int
+	BaseID             uint32
+	DrainTime          time.Duration
+	ParentShutdownTime time.Duration
+	ServiceNode        string
+	ServiceCluster     string
+	LogPath            string
+	ExtraArgs          []string
+}
+
+// AddFlags registers the options as flags, prefixed with the given prefix, e.g. --proxy-log-level.
+func (o *Options) AddFlags(flags *run.FlagSet, prefix string) {
+	flags.StringVar(
+		&o.LogLevel,
+		prefix+"-log-level",
+		o.LogLevel,
+		"Log level: trace, debug, info, warning, error, critical or off")
+	flags.StringVar(
+		&o.ComponentLogLevel,
+		prefix+"-component-log-level",
+		o.ComponentLogLevel,
+		"Comma separated list of component log levels, e.g. upstream:debug,connection:trace")
+	flags.IntVar(
+		&o.Concurrency,
+		prefix+"-concurrency",
+		o.Concurrency,
+		"Number of worker threads. Default: the number of hardware threads")
+	flags.Uint32Var(
+		&o.BaseID,
+		prefix+"-base-id",
+		o.BaseID,
+		"Base ID of the shared memory regions, required to be unique for each instance on the same host")
+	flags.DurationVar(
+		&o.DrainTime,
+		prefix+"-drain-time",
+		o.DrainTime,
+		"Time to drain connections during a hot restart or a graceful stop, rounded up to seconds")
+	flags.DurationVar(
+		&o.ParentShutdownTime,
+		prefix+"-parent-shutdown-time",
+		o.ParentShutdownTime,
+		"Time to wait before shutting down the parent process during a hot restart, rounded up to seconds")
+	flags.StringVar(
+		&o.ServiceNode,
+		prefix+"-service-node",
+		o.ServiceNode,
+		"Node ID, overrides the node ID of the config")
+	flags.StringVar(
+		&o.ServiceCluster,
+		prefix+"-service-cluster",
+		o.ServiceCluster,
+		"Node cluster, overrides the node cluster of the config")
+	flags.StringVar(
+		&o.LogPath,
+		prefix+"-log-path",
+		o.LogPath,
+		"Path to the log file. Default: stderr")
+	flags.StringArrayVar(
+		&o.ExtraArgs,
+		prefix+"-extra-args",
+		o.ExtraArgs,
+		"Extra argument passed as is, repeat the flag for each argument, e.g. --"+prefix+"-extra-args=--log-format "+
+			"--"+prefix+"-extra-args=\"[%T] %v\"")
+}
+
+// Validate validates the options.
+func (o *Options) Validate() error {
+	if o.LogLevel != "" {
+		if _, ok := logLevels[o.LogLevel]; !ok {
+			return fmt.Errorf("invalid log level %q", o.LogLevel)
+		}
+	}
+	if o.ComponentLogLevel != "" {
+		for _, entry := range strings.Split(o.ComponentLogLevel, ",") {
+			parts := strings.Split(entry, ":")
+			if len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("invalid component log level %q", entry)
+			}
+			if _, ok := logLevels[parts[1]]; !ok {
+				return fmt.Errorf("invalid log level %q of component %q", parts[1], parts[0])
+			}
+		}
+	}
+	if o.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency %d", o.Concurrency)
+	}
+	if o.DrainTime < 0 {
+		return fmt.Errorf("invalid drain time %s", o.DrainTime)
+	}
+	if o.ParentShutdownTime < 0 {
+		return fmt.Errorf("invalid parent shutdown time %s", o.ParentShutdownTime)
+	}
+	return nil
+}
+
+// Args returns the command line arguments of the options.
+func (o *Options) Args() []string {
+	var args []string
+	add := func(name, value string) {
+		if value != "" {
+			args = append(args, name, value)
+		}
+	}
+	add("--log-level", o.LogLevel)
+	add("--component-log-level", o.ComponentLogLevel)
+	if o.Concurrency > 0 {
+		add("--concurrency", strconv.Itoa(o.Concurrency))
+	}
+	if o.BaseID > 0 {
+		add("--base-id", strconv.FormatUint(uint64(o.BaseID), 10))
+	}
+	if o.DrainTime > 0 {
+		add("--drain-time-s", seconds(o.DrainTime))
+	}
+	if o.ParentShutdownTime > 0 {
+		add("--parent-shutdown-time-s", seconds(o.ParentShutdownTime))
+	}
+	add("--service-node", o.ServiceNode)
+	add("--service-cluster", o.ServiceCluster)
+	add("--log-path", o.LogPath)
+	return append(args, o.ExtraArgs...)
+}
+
+// seconds returns the duration in seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+
+	"github.com/dio/rundown/internal/envoy"
+)
+
+func TestOptionsArgs(t *testing.T) {
+	var options envoy.Options
+	flags := run.NewFlagSet("test")
+	options.AddFlags(flags, "proxy")
+	require.NoError(t, flags.Parse([]string{
+		"--proxy-log-level", "debug",
+		"--proxy-component-log-level", "upstream:debug,connection:trace",
+		"--proxy-concurrency", "2",
+		"--proxy-base-id", "1",
+		"--proxy-drain-time", "1500ms",
+		"--proxy-parent-shutdown-time", "10s",
+		"--proxy-service-node", "node",
+		"--proxy-service-cluster", "cluster",
+		"--proxy-log-path", "/tmp/envoy.log",
+		"--proxy-extra-args", "--disable-hot-restart",
+		"--proxy-extra-args=--log-format",
+		"--proxy-extra-args", "[%Y-%m-%d %T] %v",
+	}))
+	require.NoError(t, options.Validate())
+	require.Equal(t, []string{
+		"--log-level", "debug",
+		"--component-log-level", "upstream:debug,connection:trace",
+		"--concurrency", "2",
+		"--base-id", "1",
+		"--drain-time-s", "2",
+		"--parent-shutdown-time-s", "10",
+		"--service-node", "node",
+		"--service-cluster", "cluster",
+		"--log-path", "/tmp/envoy.log",
+		"--disable-hot-restart", "--log-format", "[%Y-%m-%d %T] %v",
+	}, options.Args())
+
+	require.Empty(t, (&envoy.Options{}).Args())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options envoy.Options
+	}{
+		{name: "log level", options: envoy.Options{LogLevel: "verbose"}},
+		{name: "component without level", options: envoy.Options{ComponentLogLevel: "upstream"}},
+		{name: "component log level", options: envoy.Options{ComponentLogLevel: "upstream:debug,http:loud"}},
+		{name: "concurrency", options: envoy.Options{Concurrency: -1}},
+		{name: "drain time", options: envoy.Options{DrainTime: -time.Second}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.options.Validate())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UnixPrefix is the prefix of a unix domain socket address, e.g. unix:///var/run/xds.sock.
+const UnixPrefix = "unix://"
+
+// DefaultSocketMode is the default permissions of a unix domain socket file.
+const DefaultSocketMode os.FileMode = 0o600
+
+// Address returns the address to listen on, given a host and a port. When the host is a unix domain
+// socket address, the port is ignored.
+func Address(host string, port int32) string {
+	if IsUnix(host) {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// DialAddress returns the address to dial a server listening on the given host and port. When the
+// host is unspecified (empty, 0.0.0.0 or ::), the server is dialed through the loopback address.
+func DialAddress(host string, port int32) string {
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return Address(host, port)
+}
+
+// IsUnix returns true when the address is a unix domain socket address.
+func IsUnix(address string) bool {
+	return strings.HasPrefix(address, UnixPrefix)
+}
+
+// ParseSocketMode parses the octal representation of socket file permissions, e.g. "0660". An empty
+// string gives DefaultSocketMode.
+func ParseSocketMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return DefaultSocketMode, nil
+	}
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid socket mode %q: %w", mode, err)
+	}
+	return os.FileMode(parsed) & os.ModePerm, nil
+}
+
+// Listen listens on a TCP address (host:port) or a unix domain socket address. For a unix domain
+// socket, a stale socket file is removed before listening, and the created socket file is set with
+// the given permissions.
+func Listen(address string, mode os.FileMode) (net.Listener, error) {
+	if !IsUnix(address) {
+		return net.Listen("tcp", address)
+	}
+
+	path := strings.TrimPrefix(address, UnixPrefix)
+	if err := Cleanup(address); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(path, mode); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Cleanup removes the socket file of a unix domain socket address. It is a no-op for other addresses.
+// Anything other than a socket at the path is refused, and kept.
+func Cleanup(address string) error {
+	if !IsUnix(address) {
+		return nil
+	}
+	path := strings.TrimPrefix(address, UnixPrefix)
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("refusing to remove %s: not a socket", path)
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listener_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/listener"
+)
+
+func TestAddress(t *testing.T) {
+	require.Equal(t, "127.0.0.1:8080", listener.Address("127.0.0.1", 8080))
+	require.Equal(t, "unix:///tmp/xds.sock", listener.Address("unix:///tmp/xds.sock", 8080))
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sock")
+	address := listener.UnixPrefix + path
+
+	mode, err := listener.ParseSocketMode("0660")
+	require.NoError(t, err)
+
+	// A regular file is refused, and kept.
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+	_, err = listener.Listen(address, mode)
+	require.Error(t, err)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(b))
+	require.NoError(t, os.Remove(path))
+
+	// A stale socket file is removed.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	l, err := listener.Listen(address, mode)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	require.NoError(t, l.Close())
+	require.NoError(t, listener.Cleanup(address))
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	_, err = listener.ParseSocketMode("rw")
+	require.Error(t, err)
+}
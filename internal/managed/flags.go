@@ -20,6 +20,8 @@ import (
 
 	"github.com/iancoleman/strcase"
 	"github.com/tetratelabs/run"
+
+	"github.com/dio/rundown/internal/output"
 )
 
 // Flags holds common flags that can be shared across services.
@@ -31,6 +33,14 @@ type Flags struct {
 	// Titleize allows to override the titleize. You want to use this, e.g. for preserving casing
 	// for xDS (the default titleize gives Xds).
 	Titleize func(string) string
+	// Supervised registers the flags of the supervision policy, e.g. --proxy-restart-on-failure.
+	Supervised bool
+	// Policy is the supervision policy, set through the flags when supervised.
+	Policy Policy
+	// Captured registers the flags of capturing the output, e.g. --proxy-log-capture.
+	Captured bool
+	// Output is the options of capturing the output, set through the flags when captured.
+	Output output.Options
 
 	disabled bool
 	g        *run.Group
@@ -81,10 +91,97 @@ func (m *Flags) Manage(flags *run.FlagSet, g *run.Group, s run.Service) {
 		false,
 		"Disable "+title)
 
+	if m.Supervised {
+		m.managePolicy(flags, s.Name(), title)
+	}
+	if m.Captured {
+		m.manageOutput(flags, s.Name(), title)
+	}
+
 	m.g = g
 	m.s = s
 }
 
+// managePolicy registers the flags of the supervision policy.
+func (m *Flags) managePolicy(flags *run.FlagSet, name, title string) {
+	// --<name>-restart-on-failure. For example: --proxy-restart-on-failure.
+	flags.BoolVar(
+		&m.Policy.RestartOnFailure,
+		name+"-restart-on-failure",
+		false,
+		"Restart "+title+" when it fails")
+	flags.DurationVar(
+		&m.Policy.Backoff,
+		name+"-restart-backoff",
+		DefaultRestartBackoff,
+		"Time to wait before the first restart of "+title+", doubled for each subsequent restart")
+	flags.DurationVar(
+		&m.Policy.MaxBackoff,
+		name+"-restart-max-backoff",
+		DefaultRestartMaxBackoff,
+		"Maximum time to wait before a restart of "+title)
+	flags.IntVar(
+		&m.Policy.MaxRestarts,
+		name+"-max-restarts",
+		DefaultMaxRestarts,
+		"Maximum number of restarts of "+title+" within the restart window. Zero means unlimited")
+	flags.DurationVar(
+		&m.Policy.Window,
+		name+"-restart-window",
+		DefaultRestartWindow,
+		"Window of counting the restarts of "+title)
+	flags.StringVar(
+		&m.Policy.LivenessProbe,
+		name+"-liveness-probe",
+		m.Policy.LivenessProbe,
+		"Liveness probe of "+title+": http(s)://host:port/path, grpc://host:port[/service], or a service specific probe")
+	flags.DurationVar(
+		&m.Policy.LivenessProbeInitialDelay,
+		name+"-liveness-probe-initial-delay",
+		DefaultLivenessProbeInitialDelay,
+		"Time to wait after starting "+title+" before checking the liveness probe")
+	flags.DurationVar(
+		&m.Policy.LivenessProbeInterval,
+		name+"-liveness-probe-interval",
+		DefaultLivenessProbeInterval,
+		"Interval of checking the liveness probe of "+title)
+	flags.IntVar(
+		&m.Policy.LivenessProbeFailureThreshold,
+		name+"-liveness-probe-failure-threshold",
+		DefaultLivenessProbeFailureThreshold,
+		"Number of consecutive liveness probe failures before killing "+title)
+	flags.DurationVar(
+		&m.Policy.GraceTimeout,
+		name+"-stop-grace-timeout",
+		DefaultGraceTimeout,
+		"Time to wait for "+title+" to exit after each stop signal (SIGINT, then SIGTERM) before escalating to SIGKILL")
+}
+
+// manageOutput registers the flags of capturing the output.
+func (m *Flags) manageOutput(flags *run.FlagSet, name, title string) {
+	// --<name>-log-capture. For example: --proxy-log-capture.
+	flags.BoolVar(
+		&m.Output.Capture,
+		name+"-log-capture",
+		false,
+		"Capture the stdout and stderr of "+title+" line by line into the logger")
+	flags.StringVar(
+		&m.Output.File,
+		name+"-log-file",
+		m.Output.File,
+		"Path to the log file of "+title+", rotated by size")
+	flags.IntVar(
+		&m.Output.MaxSize,
+		name+"-log-file-max-size",
+		output.DefaultMaxSize,
+		"Maximum size of the log file of "+title+" in megabytes before it is rotated")
+	flags.IntVar(
+		&m.Output.MaxBackups,
+		name+"-log-file-max-backups",
+		output.DefaultMaxBackups,
+		"Maximum number of the rotated log files of "+title+" to retain")
+}
+
 // IsDisabled returns true when a managed service is disabled.
 func (m *Flags) IsDisabled() bool {
 	if m.g == nil || !m.disabled {
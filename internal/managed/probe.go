@@ -0,0 +1,98 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Probe checks whether a process is alive.
+type Probe func(ctx context.Context) error
+
+// ParseProbe parses a liveness probe, which is one of:
+//   - http://host:port/path or https://host:port/path: a GET request which returns 2xx.
+//   - grpc://host:port or grpc://host:port/service: a gRPC health check which returns SERVING.
+//   - A name of the given named probes, e.g. "ready" for the Envoy /ready endpoint.
+//
+// An empty probe gives nil.
+func ParseProbe(probe string, named map[string]Probe) (Probe, error) {
+	if probe == "" {
+		return nil, nil
+	}
+	if p, ok := named[probe]; ok {
+		return p, nil
+	}
+	u, err := url.Parse(probe)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return HTTPProbe(probe), nil
+	case "grpc":
+		if u.Host == "" {
+			return nil, fmt.Errorf("no host in %q", probe)
+		}
+		return GRPCProbe(u.Host, strings.TrimPrefix(u.Path, "/")), nil
+	}
+	return nil, fmt.Errorf("unsupported probe %q", probe)
+}
+
+// HTTPProbe returns a probe that sends a GET request to the given URL, and expects a 2xx response.
+func HTTPProbe(target string) Probe {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			return fmt.Errorf("GET %s: %s", target, res.Status)
+		}
+		return nil
+	}
+}
+
+// GRPCProbe returns a probe that checks the gRPC health of the given service at the given address.
+// An empty service checks the overall health of the server.
+func GRPCProbe(address, service string) Probe {
+	return func(ctx context.Context) error {
+		conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if res.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("%s is %s", address, res.Status)
+		}
+		return nil
+	}
+}
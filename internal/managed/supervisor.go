@@ -0,0 +1,230 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/telemetry"
+)
+
+var (
+	// DefaultRestartBackoff is the default time to wait before the first restart.
+	DefaultRestartBackoff = time.Second
+	// DefaultRestartMaxBackoff is the default maximum time to wait before a restart.
+	DefaultRestartMaxBackoff = 30 * time.Second
+	// DefaultMaxRestarts is the default maximum number of restarts within the restart window.
+	DefaultMaxRestarts = 5
+	// DefaultRestartWindow is the default window of counting the restarts.
+	DefaultRestartWindow = 5 * time.Minute
+	// DefaultLivenessProbeInitialDelay is the default time to wait after starting the process before
+	// checking the liveness probe.
+	DefaultLivenessProbeInitialDelay = 10 * time.Second
+	// DefaultLivenessProbeInterval is the default interval of checking the liveness probe.
+	DefaultLivenessProbeInterval = 10 * time.Second
+	// DefaultLivenessProbeFailureThreshold is the default number of consecutive liveness probe
+	// failures before restarting the process.
+	DefaultLivenessProbeFailureThreshold = 3
+	// DefaultGraceTimeout is the default time to wait for the process to exit after each stop signal.
+	DefaultGraceTimeout = 10 * time.Second
+)
+
+// Policy is the supervision policy of a managed process.
+type Policy struct {
+	// RestartOnFailure restarts the process when it fails.
+	RestartOnFailure bool
+	// Backoff is the time to wait before the first restart, doubled for each subsequent restart.
+	Backoff time.Duration
+	// MaxBackoff is the maximum time to wait before a restart.
+	MaxBackoff time.Duration
+	// MaxRestarts is the maximum number of restarts within Window. When it is reached, the failure is
+	// returned. Zero means unlimited.
+	MaxRestarts int
+	// Window is the window of counting the restarts.
+	Window time.Duration
+	// LivenessProbe is the liveness probe, see ParseProbe.
+	LivenessProbe string
+	// LivenessProbeInitialDelay is the time to wait after starting the process before checking the
+	// liveness probe, giving the process time to start up. Zero means no delay.
+	LivenessProbeInitialDelay time.Duration
+	// LivenessProbeInterval is the interval of checking the liveness probe.
+	LivenessProbeInterval time.Duration
+	// LivenessProbeFailureThreshold is the number of consecutive liveness probe failures before
+	// killing the process.
+	LivenessProbeFailureThreshold int
+	// GraceTimeout is the time to wait for the process to exit after each stop signal (SIGINT, then
+	// SIGTERM), before escalating to the next one, and finally SIGKILL.
+	GraceTimeout time.Duration
+}
+
+// Supervisor runs a managed process given a supervision policy.
+type Supervisor struct {
+	Name   string
+	Policy Policy
+	Probe  Probe
+	Logger telemetry.Logger
+
+	once     sync.Once
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// Supervisor returns a supervisor of the named service, given the policy set through the flags. The
+// unset durations and thresholds of the policy are set to their defaults. The named probes are the
+// service specific liveness probes, see ParseProbe.
+func (m *Flags) Supervisor(name string, logger telemetry.Logger, named map[string]Probe) (*Supervisor, error) {
+	policy := m.Policy
+	if policy.Backoff <= 0 {
+		policy.Backoff = DefaultRestartBackoff
+	}
+	if policy.MaxBackoff < policy.Backoff {
+		policy.MaxBackoff = policy.Backoff
+	}
+	if policy.Window <= 0 {
+		policy.Window = DefaultRestartWindow
+	}
+	if policy.LivenessProbeInitialDelay < 0 {
+		policy.LivenessProbeInitialDelay = 0
+	}
+	if policy.LivenessProbeInterval <= 0 {
+		policy.LivenessProbeInterval = DefaultLivenessProbeInterval
+	}
+	if policy.LivenessProbeFailureThreshold <= 0 {
+		policy.LivenessProbeFailureThreshold = DefaultLivenessProbeFailureThreshold
+	}
+	if policy.GraceTimeout <= 0 {
+		policy.GraceTimeout = DefaultGraceTimeout
+	}
+	probe, err := ParseProbe(policy.LivenessProbe, named)
+	if err != nil {
+		return nil, fmt.Errorf("invalid liveness probe: %w", err)
+	}
+	return &Supervisor{Name: name, Policy: policy, Probe: probe, Logger: logger}, nil
+}
+
+// Run runs serve, which is expected to start the process and block until it exits. When serve fails
+// and restart on failure is enabled, serve is called again after a backoff, until the maximum number
+// of restarts within the window is reached or Stop is called. While serving, when the liveness probe
+// keeps failing, kill is called to make serve fail.
+func (s *Supervisor) Run(serve func() error, kill func()) error {
+	s.once.Do(s.init)
+	backoff := s.Policy.Backoff
+	var restarts []time.Time
+	for {
+		started := time.Now()
+		ctx, cancel := context.WithCancel(context.Background())
+		if s.Probe != nil {
+			go s.probe(ctx, kill)
+		}
+		err := serve()
+		cancel()
+		if err == nil || !s.Policy.RestartOnFailure || s.stopped() {
+			return err
+		}
+
+		now := time.Now()
+		// A process that has been running longer than the maximum backoff is considered healthy, hence
+		// the backoff is reset.
+		if now.Sub(started) > s.Policy.MaxBackoff {
+			backoff = s.Policy.Backoff
+		}
+		restarts = within(restarts, now.Add(-s.Policy.Window))
+		if s.Policy.MaxRestarts > 0 && len(restarts) >= s.Policy.MaxRestarts {
+			return fmt.Errorf("%s restarted %d times within %s: %w", s.Name, len(restarts), s.Policy.Window, err)
+		}
+		restarts = append(restarts, now)
+
+		s.Logger.Error(fmt.Sprintf("%s failed, restarting", s.Name), err, "backoff", backoff.String())
+		select {
+		case <-s.stop:
+			return err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > s.Policy.MaxBackoff {
+			backoff = s.Policy.MaxBackoff
+		}
+	}
+}
+
+// Stop stops restarting the process. The running process is expected to be stopped by the caller.
+func (s *Supervisor) Stop() {
+	s.once.Do(s.init)
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// probe checks the liveness probe periodically after the initial delay, and calls kill when it keeps
+// failing.
+func (s *Supervisor) probe(ctx context.Context, kill func()) {
+	if delay := s.Policy.LivenessProbeInitialDelay; delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+	ticker := time.NewTicker(s.Policy.LivenessProbeInterval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, s.Policy.LivenessProbeInterval)
+		err := s.Probe(probeCtx)
+		cancel()
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if ctx.Err() != nil || s.stopped() {
+			return
+		}
+		if failures++; failures >= s.Policy.LivenessProbeFailureThreshold {
+			s.Logger.Error(fmt.Sprintf("%s liveness probe failed, killing", s.Name), err, "failures", failures)
+			kill()
+			return
+		}
+	}
+}
+
+func (s *Supervisor) init() {
+	s.stop = make(chan struct{})
+}
+
+func (s *Supervisor) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// within returns the times after the given time.
+func within(times []time.Time, after time.Time) []time.Time {
+	for len(times) > 0 && !times[0].After(after) {
+		times = times[1:]
+	}
+	return times
+}
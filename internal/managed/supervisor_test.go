@@ -0,0 +1,196 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/run"
+	"github.com/tetratelabs/telemetry"
+
+	"github.com/dio/rundown/internal/managed"
+)
+
+type service struct{}
+
+func (service) Name() string { return "proxy" }
+
+func (service) Serve() error { return nil }
+
+func (service) GracefulStop() {}
+
+func TestFlagsPolicy(t *testing.T) {
+	m := &managed.Flags{Supervised: true}
+	flags := run.NewFlagSet("test")
+	m.Manage(flags, &run.Group{}, service{})
+	require.NoError(t, flags.Parse([]string{
+		"--proxy-restart-on-failure",
+		"--proxy-max-restarts", "2",
+		"--proxy-liveness-probe", "grpc://127.0.0.1:8080/envoy",
+	}))
+	require.True(t, m.Policy.RestartOnFailure)
+	require.Equal(t, 2, m.Policy.MaxRestarts)
+	require.Equal(t, managed.DefaultRestartBackoff, m.Policy.Backoff)
+
+	_, err := m.Supervisor("proxy", telemetry.NoopLogger(), nil)
+	require.NoError(t, err)
+	m.Policy.LivenessProbe = "ready"
+	_, err = m.Supervisor("proxy", telemetry.NoopLogger(), nil)
+	require.Error(t, err)
+	_, err = m.Supervisor("proxy", telemetry.NoopLogger(), map[string]managed.Probe{
+		"ready": func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+}
+
+func TestSupervisorRestarts(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		name   string
+		policy managed.Policy
+		runs   int
+		err    error
+	}{
+		{
+			name: "no restart",
+			runs: 1,
+			err:  failure,
+		},
+		{
+			name:   "max restarts",
+			policy: managed.Policy{RestartOnFailure: true, MaxRestarts: 2},
+			runs:   3,
+			err:    failure,
+		},
+		{
+			name:   "recovered",
+			policy: managed.Policy{RestartOnFailure: true, MaxRestarts: 5},
+			runs:   4,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.policy.Backoff = time.Millisecond
+			m := &managed.Flags{Policy: tc.policy}
+			s, err := m.Supervisor("test", telemetry.NoopLogger(), nil)
+			require.NoError(t, err)
+
+			runs := 0
+			err = s.Run(func() error {
+				runs++
+				if runs == 4 {
+					return nil
+				}
+				return failure
+			}, func() {})
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.runs, runs)
+		})
+	}
+}
+
+func TestSupervisorStop(t *testing.T) {
+	m := &managed.Flags{Policy: managed.Policy{RestartOnFailure: true, Backoff: time.Hour}}
+	s, err := m.Supervisor("test", telemetry.NoopLogger(), nil)
+	require.NoError(t, err)
+
+	runs := 0
+	// Stopping is safe to be called concurrently.
+	for i := 0; i < 2; i++ {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			s.Stop()
+		}()
+	}
+	err = s.Run(func() error {
+		runs++
+		return errors.New("failed")
+	}, func() {})
+	require.Error(t, err)
+	require.Equal(t, 1, runs)
+}
+
+func TestSupervisorLivenessProbe(t *testing.T) {
+	var unhealthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&unhealthy) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	m := &managed.Flags{Policy: managed.Policy{
+		LivenessProbe:                 server.URL + "/ready",
+		LivenessProbeInterval:         5 * time.Millisecond,
+		LivenessProbeFailureThreshold: 2,
+	}}
+	s, err := m.Supervisor("test", telemetry.NoopLogger(), nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Probe(context.Background()))
+	atomic.StoreInt32(&unhealthy, 1)
+	require.Error(t, s.Probe(context.Background()))
+
+	killed := make(chan struct{})
+	err = s.Run(func() error {
+		<-killed
+		return errors.New("killed")
+	}, func() { close(killed) })
+	require.EqualError(t, err, "killed")
+}
+
+func TestSupervisorLivenessProbeInitialDelay(t *testing.T) {
+	started := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The process takes a while to start up.
+		if time.Since(started) < 50*time.Millisecond {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	m := &managed.Flags{Policy: managed.Policy{
+		LivenessProbe:                 server.URL + "/ready",
+		LivenessProbeInitialDelay:     100 * time.Millisecond,
+		LivenessProbeInterval:         5 * time.Millisecond,
+		LivenessProbeFailureThreshold: 2,
+	}}
+	s, err := m.Supervisor("test", telemetry.NoopLogger(), nil)
+	require.NoError(t, err)
+
+	var killed int32
+	err = s.Run(func() error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	}, func() { atomic.StoreInt32(&killed, 1) })
+	require.NoError(t, err)
+	require.Zero(t, atomic.LoadInt32(&killed))
+}
+
+func TestParseProbe(t *testing.T) {
+	for _, probe := range []string{"tcp://127.0.0.1:80", "grpc:///service", "ready"} {
+		_, err := managed.ParseProbe(probe, nil)
+		require.Error(t, err, probe)
+	}
+	probe, err := managed.ParseProbe("", nil)
+	require.NoError(t, err)
+	require.Nil(t, probe)
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"sync"
+)
+
+// MaxLineSize is the maximum size of a line. A longer line is split.
+const MaxLineSize = 64 * 1024
+
+// LineWriter is an io.Writer that calls emit for each written line, without the line ending.
+type LineWriter struct {
+	mu   sync.Mutex
+	emit func(line string)
+	buf  []byte
+}
+
+// NewLineWriter returns a new LineWriter.
+func NewLineWriter(emit func(line string)) *LineWriter {
+	return &LineWriter{emit: emit}
+}
+
+// Write buffers p, and emits the completed lines.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emitLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	for len(w.buf) >= MaxLineSize {
+		w.emitLine(w.buf[:MaxLineSize])
+		w.buf = w.buf[MaxLineSize:]
+	}
+	// Release the consumed part of the buffer.
+	w.buf = append([]byte(nil), w.buf...)
+	return len(p), nil
+}
+
+// Close emits the pending incomplete line.
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emitLine(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *LineWriter) emitLine(line []byte) {
+	w.emit(string(bytes.TrimSuffix(line, []byte("\r"))))
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package output captures the output of managed processes.
+package output
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/tetratelabs/telemetry"
+)
+
+var (
+	// DefaultMaxSize is the default maximum size of a log file in megabytes before it is rotated.
+	DefaultMaxSize = 100
+	// DefaultMaxBackups is the default maximum number of the rotated log files to retain.
+	DefaultMaxBackups = 3
+)
+
+// Level is the level of a captured line.
+type Level int
+
+// The levels of captured lines.
+const (
+	Info Level = iota
+	Debug
+	Error
+)
+
+// Parser parses a captured line into its level, message and additional key values.
+type Parser func(line string) (level Level, msg string, kv []interface{})
+
+// Options holds the options of capturing the output of a process.
+type Options struct {
+	// Capture captures the output line by line into the logger.
+	Capture bool
+	// File is the path of the log file the output is written into. Empty means no log file.
+	File string
+	// MaxSize is the maximum size of the log file in megabytes before it is rotated.
+	MaxSize int
+	// MaxBackups is the maximum number of the rotated log files to retain.
+	MaxBackups int
+}
+
+// Output holds the writers of the output streams of a process. It is safe to share an Output across
+// multiple processes.
+type Output struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	closers []io.Closer
+}
+
+// New returns the output of the named process, given the options. Without any option, the output is
+// written to os.Stdout and os.Stderr. When capturing, each line is logged with the service name and
+// the stream (stdout or stderr), and parsed with the parser, when set.
+func New(name string, logger telemetry.Logger, opts Options, parse Parser) (*Output, error) {
+	o := &Output{Stdout: os.Stdout, Stderr: os.Stderr}
+	if !opts.Capture && opts.File == "" {
+		return o, nil
+	}
+
+	var stdout, stderr []io.Writer
+	if opts.File != "" {
+		f, err := NewRotatingFile(opts.File, opts.MaxSize, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		o.closers = append(o.closers, f)
+		// Both streams write into the same file.
+		file := &lockedWriter{w: f}
+		stdout, stderr = append(stdout, file), append(stderr, file)
+	}
+	if opts.Capture {
+		out := NewLineWriter(logLine(logger, parse, "service", name, "stream", "stdout"))
+		errOut := NewLineWriter(logLine(logger, parse, "service", name, "stream", "stderr"))
+		o.closers = append(o.closers, out, errOut)
+		stdout, stderr = append(stdout, out), append(stderr, errOut)
+	}
+	o.Stdout, o.Stderr = io.MultiWriter(stdout...), io.MultiWriter(stderr...)
+	return o, nil
+}
+
+// Close flushes the pending lines, and closes the log file.
+func (o *Output) Close() error {
+	var err error
+	for _, c := range o.closers {
+		if closeErr := c.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// logLine returns a function that logs a line, with the given key values.
+func logLine(logger telemetry.Logger, parse Parser, kv ...interface{}) func(string) {
+	return func(line string) {
+		level, msg, extra := Info, line, []interface{}(nil)
+		if parse != nil {
+			level, msg, extra = parse(line)
+		}
+		fields := append(append([]interface{}{}, kv...), extra...)
+		switch level {
+		case Debug:
+			logger.Debug(msg, fields...)
+		case Error:
+			logger.Error(msg, nil, fields...)
+		default:
+			logger.Info(msg, fields...)
+		}
+	}
+}
+
+// lockedWriter serializes the writes to the underlying writer.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
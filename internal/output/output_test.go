@@ -0,0 +1,150 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/telemetry"
+
+	"github.com/dio/rundown/internal/output"
+)
+
+// recorder records the logged lines.
+type recorder struct {
+	telemetry.Logger
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recorder) Debug(msg string, kv ...interface{}) { r.record("debug", msg, kv) }
+
+func (r *recorder) Info(msg string, kv ...interface{}) { r.record("info", msg, kv) }
+
+func (r *recorder) Error(msg string, _ error, kv ...interface{}) { r.record("error", msg, kv) }
+
+func (r *recorder) record(level, msg string, kv []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf("%s %s %v", level, msg, kv))
+}
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := output.NewLineWriter(func(line string) { lines = append(lines, line) })
+	_, err := w.Write([]byte("first\r\nsec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\n\nthird"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second", ""}, lines)
+	require.NoError(t, w.Close())
+	require.Equal(t, []string{"first", "second", "", "third"}, lines)
+
+	lines = nil
+	_, err = w.Write([]byte(strings.Repeat("a", output.MaxLineSize+1)))
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	require.Len(t, lines[0], output.MaxLineSize)
+}
+
+func TestNew(t *testing.T) {
+	o, err := output.New("proxy", telemetry.NoopLogger(), output.Options{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, os.Stdout, o.Stdout)
+	require.Equal(t, os.Stderr, o.Stderr)
+
+	logger := &recorder{Logger: telemetry.NoopLogger()}
+	file := filepath.Join(t.TempDir(), "logs", "proxy.log")
+	parse := func(line string) (output.Level, string, []interface{}) {
+		if strings.HasPrefix(line, "E ") {
+			return output.Error, line[2:], []interface{}{"parsed", true}
+		}
+		return output.Info, line, nil
+	}
+	o, err = output.New("proxy", logger, output.Options{Capture: true, File: file}, parse)
+	require.NoError(t, err)
+	_, err = o.Stdout.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	_, err = o.Stderr.Write([]byte("E failed\npartial"))
+	require.NoError(t, err)
+	require.NoError(t, o.Close())
+
+	require.Equal(t, []string{
+		"info hello [service proxy stream stdout]",
+		"error failed [service proxy stream stderr parsed true]",
+		"info partial [service proxy stream stderr]",
+	}, logger.lines)
+	b, err := os.ReadFile(file) //nolint:gosec
+	require.NoError(t, err)
+	require.Equal(t, "hello\nE failed\npartial", string(b))
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envoy.log")
+	f, err := output.NewRotatingFile(path, 1, 2)
+	require.NoError(t, err)
+	chunk := []byte(strings.Repeat("a", 700*1024))
+	for i := 0; i < 4; i++ {
+		_, err = f.Write(chunk)
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(chunk)), info.Size())
+	}
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileRotateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envoy.log")
+	f, err := output.NewRotatingFile(path, 1, 1)
+	require.NoError(t, err)
+	// The file can't be renamed to its backup, a non-empty directory.
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "busy"), 0o750))
+
+	chunk := []byte(strings.Repeat("a", 700*1024))
+	_, err = f.Write(chunk)
+	require.NoError(t, err)
+	_, err = f.Write(chunk)
+	require.Error(t, err)
+
+	// The file is reopened, the next writes are appended to it.
+	_, err = f.Write([]byte("b"))
+	require.NoError(t, err)
+
+	// It is rotated once its backup can be written.
+	require.NoError(t, os.RemoveAll(path+".1"))
+	_, err = f.Write(chunk)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	b, err := os.ReadFile(path + ".1") //nolint:gosec
+	require.NoError(t, err)
+	require.Equal(t, string(chunk)+"b", string(b))
+	b, err = os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	require.Equal(t, chunk, b)
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RotatingFile is a log file that is rotated when its size exceeds the maximum size. The rotated
+// files are named with an index suffix, e.g. envoy.log.1 is the most recent one.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile opens the log file at the given path for appending, creating its directory when
+// required. A non-positive maxSize in megabytes means no rotation.
+func NewRotatingFile(path string, maxSize, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec
+		return nil, err
+	}
+	f := &RotatingFile{path: path, maxSize: int64(maxSize) * 1024 * 1024, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write writes p to the file, rotating it first when p does not fit.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	return f.file.Close()
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files, drops the oldest one, and reopens the file. When the file can't be
+// rotated, the file at the original path is reopened, hence the next writes are appended to it.
+func (f *RotatingFile) rotate() (err error) {
+	defer func() {
+		if err != nil {
+			if openErr := f.open(); openErr != nil {
+				err = fmt.Errorf("failed to reopen %s after failing to rotate it: %v: %w", f.path, openErr, err)
+			}
+		}
+	}()
+	if err = f.file.Close(); err != nil {
+		return err
+	}
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return f.open()
+	}
+	_ = os.Remove(backup(f.path, f.maxBackups))
+	for i := f.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(backup(f.path, i), backup(f.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, backup(f.path, 1)); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func backup(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coocood/freecache"
+	pb "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/envoyproxy/ratelimit/src/config"
+	"github.com/envoyproxy/ratelimit/src/limiter"
+	"github.com/envoyproxy/ratelimit/src/memcached"
+	"github.com/envoyproxy/ratelimit/src/metrics"
+	"github.com/envoyproxy/ratelimit/src/redis"
+	"github.com/envoyproxy/ratelimit/src/server"
+	ratelimitservice "github.com/envoyproxy/ratelimit/src/service"
+	"github.com/envoyproxy/ratelimit/src/settings"
+	"github.com/envoyproxy/ratelimit/src/stats"
+	"github.com/envoyproxy/ratelimit/src/utils"
+	gostats "github.com/lyft/gostats"
+	logger "github.com/sirupsen/logrus"
+)
+
+// Runner runs the rate-limit server. This is adapted from the upstream runner
+// (src/service_cmd/runner/runner.go), which does not expose its gRPC server. Having it here allows
+// serving the gRPC server on a given listener, e.g. a unix domain socket.
+type Runner struct {
+	statsManager stats.Manager
+	settings     settings.Settings
+	listener     net.Listener
+	srv          server.Server
+	mu           sync.Mutex
+}
+
+// NewRunner returns a new runner. When the listener is not nil, the gRPC server is served on it, in
+// addition to the configured gRPC host and port.
+func NewRunner(s settings.Settings, l net.Listener) *Runner {
+	return &Runner{
+		statsManager: stats.NewStatManager(gostats.NewDefaultStore(), s),
+		settings:     s,
+		listener:     l,
+	}
+}
+
+// Run runs the server. It blocks until the server is stopped.
+func (r *Runner) Run() {
+	s := r.settings
+
+	logLevel, err := logger.ParseLevel(s.LogLevel)
+	if err != nil {
+		logger.Fatalf("Could not parse log level. %v\n", err)
+	} else {
+		logger.SetLevel(logLevel)
+	}
+	if strings.ToLower(s.LogFormat) == "json" {
+		logger.SetFormatter(&logger.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+			FieldMap: logger.FieldMap{
+				logger.FieldKeyTime: "@timestamp",
+				logger.FieldKeyMsg:  "@message",
+			},
+		})
+	}
+
+	var localCache *freecache.Cache
+	if s.LocalCacheSizeInBytes != 0 {
+		localCache = freecache.NewCache(s.LocalCacheSizeInBytes)
+	}
+
+	serverReporter := metrics.NewServerReporter(r.statsManager.GetStatsStore().ScopeWithTags("ratelimit_server", s.ExtraTags))
+
+	srv := server.NewServer(s, "ratelimit", r.statsManager, localCache, settings.GrpcUnaryInterceptor(serverReporter.UnaryServerInterceptor()))
+	r.mu.Lock()
+	r.srv = srv
+	r.mu.Unlock()
+
+	service := ratelimitservice.NewService(
+		srv.Runtime(),
+		createLimiter(srv, s, localCache, r.statsManager),
+		config.NewRateLimitConfigLoaderImpl(),
+		r.statsManager,
+		s.RuntimeWatchRoot,
+		utils.NewTimeSourceImpl(),
+		s.GlobalShadowMode,
+	)
+
+	srv.AddDebugHttpEndpoint(
+		"/rlconfig",
+		"print out the currently loaded configuration for debugging",
+		func(writer http.ResponseWriter, request *http.Request) {
+			if current := service.GetCurrentConfig(); current != nil {
+				_, _ = io.WriteString(writer, current.Dump())
+			}
+		})
+
+	srv.AddJsonHandler(service)
+
+	pb.RegisterRateLimitServiceServer(srv.GrpcServer(), service)
+
+	if r.listener != nil {
+		go func() {
+			logger.Warnf("Listening for gRPC on '%s'", r.listener.Addr())
+			if err := srv.GrpcServer().Serve(r.listener); err != nil {
+				logger.Errorf("Failed to serve gRPC on '%s': %v", r.listener.Addr(), err)
+			}
+		}()
+	}
+
+	srv.Start()
+}
+
+// Stop stops the server, including serving on the given listener.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	srv := r.srv
+	r.mu.Unlock()
+	if srv != nil {
+		srv.Stop()
+	}
+}
+
+func createLimiter(srv server.Server, s settings.Settings, localCache *freecache.Cache, statsManager stats.Manager) limiter.RateLimitCache {
+	switch s.BackendType {
+	case "redis", "":
+		return redis.NewRateLimiterCacheImplFromSettings(
+			s,
+			localCache,
+			srv,
+			utils.NewTimeSourceImpl(),
+			rand.New(utils.NewLockedSource(time.Now().Unix())), //nolint:gosec
+			s.ExpirationJitterMaxSeconds,
+			statsManager,
+		)
+	case "memcache":
+		return memcached.NewRateLimitCacheImplFromSettings(
+			s,
+			utils.NewTimeSourceImpl(),
+			rand.New(utils.NewLockedSource(time.Now().Unix())), //nolint:gosec
+			localCache,
+			srv.Scope(),
+			statsManager)
+	default:
+		logger.Fatalf("Invalid setting for BackendType: %s", s.BackendType)
+		panic("This line should not be reachable")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/envoyproxy/ratelimit/src/settings"
 
 	settingsv1 "github.com/dio/rundown/generated/ratelimit/settings/v1"
+	"github.com/dio/rundown/internal/listener"
 )
 
 func NewSettings(s *settingsv1.Settings) settings.Settings { //nolint:gocyclo
@@ -229,5 +230,13 @@ func NewSettings(s *settingsv1.Settings) settings.Settings { //nolint:gocyclo
 	if s.GlobalShadownMode != nil {
 		c.GlobalShadowMode = s.GlobalShadownMode.Value
 	}
+
+	// The rate-limit server in this version always listens on TCP for gRPC. When the gRPC host is a
+	// unix domain socket address, it is served by Runner, and the TCP listener is bound to an
+	// ephemeral loopback port instead.
+	if listener.IsUnix(c.GrpcHost) {
+		c.GrpcHost = "127.0.0.1"
+		c.GrpcPort = 0
+	}
 	return c
 }
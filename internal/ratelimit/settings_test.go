@@ -49,4 +49,10 @@ func TestNewSettings(t *testing.T) {
 	require.Equal(t, c1.Port, int(s1.Port.Value))
 	require.Equal(t, c1.GrpcHost, s1.GrpcHost.Value)
 	require.Equal(t, c1.GrpcPort, int(s1.GrpcPort.Value))
+
+	// The upstream gRPC listener is moved to an ephemeral loopback port for a unix domain socket.
+	s1.GrpcHost = &wrapperspb.StringValue{Value: "unix:///tmp/ratelimit.sock"}
+	c1 = ratelimit.NewSettings(&s1)
+	require.Equal(t, "127.0.0.1", c1.GrpcHost)
+	require.Equal(t, 0, c1.GrpcPort)
 }
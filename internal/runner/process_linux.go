@@ -0,0 +1,67 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// setProcessGroup starts the cmd in its own process group, and makes it killed when its parent dies.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+}
+
+// startCmd starts the cmd from a goroutine locked to its OS thread until the process exits. The parent
+// death signal is sent when the thread that started the process exits, rather than the parent process
+// (see golang/go#27505), hence the thread is kept until then.
+func startCmd(cmd *exec.Cmd, exited <-chan struct{}) error {
+	started := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		if err := cmd.Start(); err != nil {
+			started <- err
+			return
+		}
+		started <- nil
+		<-exited
+	}()
+	return <-started
+}
+
+// signalGroup sends the signal to the process group of the cmd.
+func signalGroup(cmd *exec.Cmd, sig syscall.Signal) error {
+	return syscall.Kill(-cmd.Process.Pid, sig)
+}
+
+// waitExited blocks until the process exits, without reaping it. Hence, its pid is not reused while
+// its leftover descendants are killed. It returns false when waiting fails.
+func waitExited(pid int) bool {
+	const pPID = 1 // P_PID of waitid(2).
+	var siginfo [16]uint64
+	for {
+		_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, pPID, uintptr(pid),
+			uintptr(unsafe.Pointer(&siginfo)), syscall.WEXITED|syscall.WNOWAIT, 0, 0)
+		if errno != syscall.EINTR {
+			return errno == 0
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package runner
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup does nothing, since process groups are not supported.
+func setProcessGroup(*exec.Cmd) {}
+
+// startCmd starts the cmd.
+func startCmd(cmd *exec.Cmd, _ <-chan struct{}) error {
+	return cmd.Start()
+}
+
+// signalGroup kills the process, since sending signals is not supported.
+func signalGroup(cmd *exec.Cmd, _ syscall.Signal) error {
+	return cmd.Process.Kill()
+}
+
+// waitExited returns false right away.
+func waitExited(int) bool {
+	return false
+}
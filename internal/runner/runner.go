@@ -1,5 +1,3 @@
-package runner
-
 // Copyright 2022 Dhi Aurrahman
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -14,55 +12,197 @@ package runner
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+package runner
+
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"os/signal"
+	"path/filepath"
+	"sync"
 	"syscall"
-
-	"github.com/dio/rundown/internal/archives"
+	"time"
 )
 
-// Run runs the prepared cmd, given an archive.
-func Run(cmd *exec.Cmd, archive archives.Archive) (int, error) {
-	err := cmd.Start()
-	if err != nil {
-		return 1, fmt.Errorf("failed to start %s: %w", archive.BinaryName(), err)
+// DefaultGraceTimeout is the default time to wait for a process to exit after each stop signal.
+var DefaultGraceTimeout = 10 * time.Second
+
+// Process is a managed process of a binary. It is started in its own process group, hence it is
+// signalled only through Stop and Kill; on Linux, it is killed when the current process dies. A
+// process can be started again once it exits.
+type Process struct {
+	// Name is the name of the process used in errors. Default: the base name of the binary.
+	Name   string
+	Binary string
+	Args   []string
+	// Stdout and Stderr are the output streams of the process. Default: os.Stdout and os.Stderr.
+	Stdout io.Writer
+	Stderr io.Writer
+	// GraceTimeout is the time to wait for the process to exit after each stop signal (SIGINT, then
+	// SIGTERM), before escalating to the next one, and finally SIGKILL.
+	GraceTimeout time.Duration
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	exitCode int
+	err      error
+}
+
+// NewProcess returns a new process of the given binary and arguments.
+func NewProcess(binary string, args ...string) *Process {
+	return &Process{
+		Name:         filepath.Base(binary),
+		Binary:       binary,
+		Args:         args,
+		Stdout:       os.Stdout,
+		Stderr:       os.Stderr,
+		GraceTimeout: DefaultGraceTimeout,
+	}
+}
+
+// Start starts the process. When the context is done, the process is stopped as in Stop.
+func (p *Process) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running() {
+		return fmt.Errorf("%s is already running", p.Name)
+	}
+
+	cmd := exec.Command(p.Binary, p.Args...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout, cmd.Stderr = p.Stdout, p.Stderr
+	setProcessGroup(cmd)
+	exited := make(chan struct{})
+	if err := startCmd(cmd, exited); err != nil {
+		return fmt.Errorf("failed to start %s: %w", p.Name, err)
 	}
 
-	// Buffered, since caught by sigchanyzer: misuse of unbuffered os.Signal channel as argument to
-	// signal.Notify.
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	p.cmd, p.exited, p.exitCode, p.err = cmd, exited, -1, nil
+	go p.wait(cmd, exited)
 	go func() {
-		s := <-c
-		_ = cmd.Process.Signal(s)
-		// TODO(dio): Handle windows.
+		select {
+		case <-ctx.Done():
+			stop(cmd, p.GraceTimeout, exited)
+		case <-exited:
+		}
 	}()
+	return nil
+}
 
-	err = cmd.Wait()
-	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			waitStatus, _ := exitError.Sys().(syscall.WaitStatus)
-			return waitStatus.ExitStatus(), nil
-		}
-		return 1, fmt.Errorf("failed to launch %s: %v", archive.BinaryName(), err)
+// Wait blocks until the started process exits. A non-zero exit code is not an error, see ExitCode.
+func (p *Process) Wait() error {
+	p.mu.Lock()
+	exited := p.exited
+	p.mu.Unlock()
+	if exited == nil {
+		return fmt.Errorf("%s is not started", p.Name)
 	}
-	return 0, nil
+	<-exited
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
 }
 
-// MakeCmd returns initialized command.
-func MakeCmd(binary string, args []string, out io.Writer) *exec.Cmd {
-	cmd := exec.Command(binary, args...) //nolint:gosec
-	cmd.Stdin = os.Stdin
-	// TODO(dio): Kill all child processes.
-	if out == nil {
-		cmd.Stdout = os.Stdout
-	} else {
-		cmd.Stdout = out
+// ExitCode returns the exit code of the exited process. It is -1 when the process is not started, is
+// still running, or is terminated by a signal.
+func (p *Process) ExitCode() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exited == nil || p.running() {
+		return -1
+	}
+	return p.exitCode
+}
+
+// Stop stops the running process by sending SIGINT to its process group. When it does not exit within
+// the grace timeout, SIGTERM is sent, and then SIGKILL after another grace timeout. It blocks until the
+// process exits or SIGKILL is sent.
+func (p *Process) Stop() {
+	p.mu.Lock()
+	cmd, exited := p.cmd, p.exited
+	p.mu.Unlock()
+	if cmd != nil {
+		stop(cmd, p.GraceTimeout, exited)
+	}
+}
+
+// Kill kills the process group of the running process.
+func (p *Process) Kill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running() {
+		_ = signalGroup(p.cmd, syscall.SIGKILL)
+	}
+}
+
+// running returns true when the process is started and has not exited. It must be called with p.mu
+// held.
+func (p *Process) running() bool {
+	if p.exited == nil {
+		return false
+	}
+	select {
+	case <-p.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// wait waits for the started cmd to exit, and records its exit code. The leftover descendants in the
+// process group of the cmd are killed once it exits, since they may keep its output open. When the
+// cmd cannot be waited for without reaping it, they are killed once it is reaped instead.
+func (p *Process) wait(cmd *exec.Cmd, exited chan struct{}) {
+	waited := waitExited(cmd.Process.Pid)
+	if waited {
+		_ = signalGroup(cmd, syscall.SIGKILL)
+	}
+	err := cmd.Wait()
+	if !waited {
+		_ = signalGroup(cmd, syscall.SIGKILL)
+	}
+
+	code := 1
+	if cmd.ProcessState != nil {
+		code = cmd.ProcessState.ExitCode()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = nil
+	} else if err != nil {
+		err = fmt.Errorf("failed to launch %s: %w", p.Name, err)
+	}
+
+	p.mu.Lock()
+	p.exitCode, p.err = code, err
+	p.mu.Unlock()
+	close(exited)
+}
+
+// stop sends SIGINT, then SIGTERM, then SIGKILL to the process group of the cmd, waiting for the grace
+// timeout between each signal until it exits.
+func stop(cmd *exec.Cmd, grace time.Duration, exited <-chan struct{}) {
+	for _, sig := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM} {
+		select {
+		case <-exited:
+			return
+		default:
+		}
+		if err := signalGroup(cmd, sig); err != nil {
+			return
+		}
+		timer := time.NewTimer(grace)
+		select {
+		case <-exited:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
 	}
-	cmd.Stderr = os.Stderr
-	return cmd
+	_ = signalGroup(cmd, syscall.SIGKILL)
 }
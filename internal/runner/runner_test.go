@@ -0,0 +1,123 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/runner"
+)
+
+func init() {
+	// Keep the tests off the main thread, since it is never terminated, see
+	// TestProcessOutlivesStartingThread.
+	runtime.LockOSThread()
+}
+
+func TestProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("/bin/sh is required")
+	}
+	p := runner.NewProcess("/bin/sh", "-c", "exit 3")
+	require.Equal(t, "sh", p.Name)
+	require.Error(t, p.Wait())
+	require.Equal(t, -1, p.ExitCode())
+
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Wait())
+	require.Equal(t, 3, p.ExitCode())
+
+	// The process can be started again once it exits.
+	p.Args = []string{"-c", "exit 0"}
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Wait())
+	require.Equal(t, 0, p.ExitCode())
+
+	p = runner.NewProcess("/non/existent")
+	require.Error(t, p.Start(context.Background()))
+}
+
+func TestProcessContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported")
+	}
+	p := runner.NewProcess("/bin/sh", "-c", "while true; do sleep 1; done")
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, p.Start(ctx))
+	require.Error(t, p.Start(ctx))
+	require.Equal(t, -1, p.ExitCode())
+
+	started := time.Now()
+	cancel()
+	require.NoError(t, p.Wait())
+	require.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestProcessOutlivesStartingThread(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("/bin/sh is required")
+	}
+	p := runner.NewProcess("/bin/sh", "-c", "sleep 0.5; exit 3")
+	started := make(chan error)
+	go func() {
+		// The OS thread of a locked goroutine exits with the goroutine.
+		runtime.LockOSThread()
+		started <- p.Start(context.Background())
+	}()
+	require.NoError(t, <-started)
+	require.NoError(t, p.Wait())
+	require.Equal(t, 3, p.ExitCode())
+}
+
+func TestProcessStopEscalates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported")
+	}
+	// The shell ignores SIGINT and SIGTERM, hence only SIGKILL stops it.
+	p := runner.NewProcess("/bin/sh", "-c", "trap '' INT TERM; while true; do sleep 1; done")
+	p.GraceTimeout = 50 * time.Millisecond
+	require.NoError(t, p.Start(context.Background()))
+	// Wait for the shell to set the traps.
+	time.Sleep(100 * time.Millisecond)
+
+	started := time.Now()
+	p.Stop()
+	require.NoError(t, p.Wait())
+	require.Less(t, time.Since(started), 5*time.Second)
+	require.Equal(t, -1, p.ExitCode())
+}
+
+func TestProcessKillsLeftovers(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("waiting without reaping is only supported on linux")
+	}
+	var out bytes.Buffer
+	// The background sleep holds the output open after the shell exits.
+	p := runner.NewProcess("/bin/sh", "-c", "sleep 30 & echo started")
+	p.Stdout = &out
+	require.NoError(t, p.Start(context.Background()))
+
+	started := time.Now()
+	require.NoError(t, p.Wait())
+	require.Less(t, time.Since(started), 5*time.Second)
+	require.Equal(t, 0, p.ExitCode())
+	require.Equal(t, "started\n", out.String())
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is the default duration to wait for subsequent events before checking for changes.
+var DefaultDebounce = 100 * time.Millisecond
+
+// Watcher watches a set of files or directories, and notifies when the content of any of them
+// changes. Instead of watching the paths directly, it watches their parent directories, so atomic
+// writes (write to a temporary file, then rename) and Kubernetes configmap updates (symlink swap)
+// are also detected.
+type Watcher struct {
+	// Debounce is the duration to wait for subsequent events before checking for changes.
+	Debounce time.Duration
+
+	paths    []string
+	digests  map[string][sha256.Size]byte
+	notifier *fsnotify.Watcher
+}
+
+// New returns a new watcher for the given paths. A path can be a file or a directory. For a
+// directory, only the regular files directly inside it are considered.
+func New(paths ...string) (*Watcher, error) {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[string]struct{})
+	for _, path := range paths {
+		dirs := []string{filepath.Dir(path)}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		for _, dir := range dirs {
+			if _, ok := watched[dir]; ok {
+				continue
+			}
+			if err = notifier.Add(dir); err != nil {
+				_ = notifier.Close()
+				return nil, err
+			}
+			watched[dir] = struct{}{}
+		}
+	}
+
+	w := &Watcher{
+		Debounce: DefaultDebounce,
+		paths:    paths,
+		digests:  make(map[string][sha256.Size]byte),
+		notifier: notifier,
+	}
+	w.changed() // Record the initial digests.
+	return w, nil
+}
+
+// Run blocks and calls onChange every time the content of the watched paths changes, until the
+// context is done. Errors reported by the underlying notifier are passed to onError, when set.
+func (w *Watcher) Run(ctx context.Context, onChange func(), onError func(error)) {
+	defer w.notifier.Close()
+
+	var (
+		timer   *time.Timer
+		trigger <-chan time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-w.notifier.Events:
+			if !ok {
+				return
+			}
+			// Wait a bit for subsequent events, e.g. when a file is written in multiple steps.
+			if timer == nil {
+				timer = time.NewTimer(w.Debounce)
+				trigger = timer.C
+			}
+		case <-trigger:
+			timer, trigger = nil, nil
+			if w.changed() {
+				onChange()
+			}
+		case err, ok := <-w.notifier.Errors:
+			if !ok {
+				return
+			}
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// changed recomputes the digests of the watched paths, and returns true if any of them changed.
+func (w *Watcher) changed() bool {
+	changed := false
+	for _, path := range w.paths {
+		digest := digestOf(path)
+		if previous, ok := w.digests[path]; !ok || previous != digest {
+			w.digests[path] = digest
+			changed = true
+		}
+	}
+	return changed
+}
+
+// digestOf returns the digest of a file, or the combined digest of regular files inside a
+// directory. A missing path has the digest of an empty content.
+func digestOf(path string) [sha256.Size]byte {
+	info, err := os.Stat(path)
+	if err != nil {
+		return sha256.Sum256(nil)
+	}
+	if !info.IsDir() {
+		b, _ := os.ReadFile(path) //nolint:gosec
+		return sha256.Sum256(b)
+	}
+
+	entries, _ := os.ReadDir(path)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		file := filepath.Join(path, name)
+		info, err := os.Stat(file) // Follows symlinks.
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		b, _ := os.ReadFile(file) //nolint:gosec
+		digest := sha256.Sum256(b)
+		h.Write([]byte(name))
+		h.Write(digest[:])
+	}
+	var digest [sha256.Size]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
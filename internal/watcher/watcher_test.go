@@ -0,0 +1,63 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dio/rundown/internal/watcher"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 8080"), 0o600))
+
+	w, err := watcher.New(path)
+	require.NoError(t, err)
+	w.Debounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 10)
+	go w.Run(ctx, func() { changes <- struct{}{} }, nil)
+
+	// Writing a file next to the watched one is not a change.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("port: 8081"), 0o600))
+	// Writing the same content is not a change.
+	require.NoError(t, os.WriteFile(path, []byte("port: 8080"), 0o600))
+	// Atomic write, i.e. rename a temporary file to the watched one.
+	tmp := filepath.Join(dir, ".config.yaml.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("port: 8082"), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting a change")
+	}
+	select {
+	case <-changes:
+		t.Fatal("expecting only one change")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
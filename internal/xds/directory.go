@@ -0,0 +1,138 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"sigs.k8s.io/yaml"
+)
+
+const typeURLPrefix = "type.googleapis.com/"
+
+// LoadResources loads the resources from the YAML or JSON files in the given directory, grouped by
+// their type URLs. Each file holds a single resource with its "@type". Sub-directories, hidden files
+// (e.g. the "..data" of a mounted Kubernetes configmap) and files with other extensions are ignored.
+func LoadResources(dir string) (map[resource.Type][]types.Resource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[resource.Type][]types.Resource)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		typeURL, parsed, err := loadResource(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource file %s: %w", path, err)
+		}
+		resources[typeURL] = append(resources[typeURL], parsed)
+	}
+	return resources, nil
+}
+
+// loadResource loads a typed and validated resource from a file. The type is resolved from the
+// "@type" field through the global registry.
+func loadResource(path string) (resource.Type, types.Resource, error) {
+	b, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return "", nil, err
+	}
+	if filepath.Ext(path) != ".json" {
+		if b, err = yaml.YAMLToJSON(b); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var typed struct {
+		Type string `json:"@type"`
+	}
+	if err = json.Unmarshal(b, &typed); err != nil {
+		return "", nil, err
+	}
+	if typed.Type == "" {
+		return "", nil, errors.New(`"@type" is required`)
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(typed.Type)
+	if err != nil {
+		return "", nil, fmt.Errorf("unknown type %q: %w", typed.Type, err)
+	}
+
+	var wrapped anypb.Any
+	if err = protojson.Unmarshal(b, &wrapped); err != nil {
+		return "", nil, err
+	}
+	parsed := messageType.New().Interface()
+	if err = wrapped.UnmarshalTo(parsed); err != nil {
+		return "", nil, err
+	}
+	// The type can be resolved with any prefix, hence the type URL is normalized.
+	typeURL := typeURLPrefix + string(messageType.Descriptor().FullName())
+	if cache.GetResponseType(typeURL) == types.UnknownType {
+		return "", nil, fmt.Errorf("unsupported resource type %q", typed.Type)
+	}
+	if v, ok := parsed.(validator); ok {
+		if err = v.ValidateAll(); err != nil {
+			return "", nil, err
+		}
+	}
+	return typeURL, parsed, nil
+}
+
+// mergeResources appends the resources of src to dst.
+func mergeResources(dst, src map[resource.Type][]types.Resource) {
+	for typeURL, resources := range src {
+		dst[typeURL] = append(dst[typeURL], resources...)
+	}
+}
+
+// ReplaceResources adds the resources of src to dst. A resource of src replaces the resource of dst
+// with the same type and name.
+func ReplaceResources(dst, src map[resource.Type][]types.Resource) {
+	for typeURL, resources := range src {
+		names := make(map[string]struct{}, len(resources))
+		for _, r := range resources {
+			names[cache.GetResourceName(r)] = struct{}{}
+		}
+		kept := make([]types.Resource, 0, len(dst[typeURL])+len(resources))
+		for _, r := range dst[typeURL] {
+			if _, ok := names[cache.GetResourceName(r)]; !ok {
+				kept = append(kept, r)
+			}
+		}
+		dst[typeURL] = append(kept, resources...)
+	}
+}
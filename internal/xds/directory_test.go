@@ -0,0 +1,68 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestLoadResources(t *testing.T) {
+	resources, err := xds.LoadResources("testdata/resources")
+	require.NoError(t, err)
+	require.Len(t, resources[resource.ClusterType], 1)
+	require.Len(t, resources[resource.RouteType], 1)
+	require.Len(t, resources[resource.ListenerType], 1)
+
+	snapshots, err := xds.NewSnapshots("1", &configv1.Config{ResourcesDir: "testdata/resources"})
+	require.NoError(t, err)
+	snapshot := snapshots[xds.DefaultNodeGroup]
+	require.Contains(t, snapshot.GetResources(resource.ClusterType), "example_proxy_cluster")
+	require.Contains(t, snapshot.GetResources(resource.RouteType), "local_route")
+	require.Contains(t, snapshot.GetResources(resource.ListenerType), "listener_0")
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing type", "name: a\n"},
+		{"unknown type", "\"@type\": type.googleapis.com/unknown.Message\nname: a\n"},
+		{"unsupported type", "\"@type\": type.googleapis.com/envoy.config.core.v3.Node\nid: a\n"},
+		{"invalid resource", "\"@type\": type.googleapis.com/envoy.config.cluster.v3.Cluster\nname: a\nconnect_timeout: -1s\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "resource.yaml"), []byte(test.content), 0o600))
+			_, err := xds.LoadResources(dir)
+			require.Error(t, err)
+		})
+	}
+
+	// Hidden files and files with other extensions are ignored.
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden.yaml"), []byte("name: a\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Resources\n"), 0o600))
+	resources, err = xds.LoadResources(dir)
+	require.NoError(t, err)
+	require.Empty(t, resources)
+}
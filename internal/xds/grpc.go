@@ -0,0 +1,86 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// ValidateGrpcServer validates the gRPC server settings.
+func ValidateGrpcServer(cfg *configv1.GrpcServer) error {
+	for name, d := range map[string]*durationpb.Duration{
+		"keepalive_time":           cfg.GetKeepaliveTime(),
+		"keepalive_timeout":        cfg.GetKeepaliveTimeout(),
+		"keepalive_min_time":       cfg.GetKeepaliveMinTime(),
+		"max_connection_idle":      cfg.GetMaxConnectionIdle(),
+		"max_connection_age":       cfg.GetMaxConnectionAge(),
+		"max_connection_age_grace": cfg.GetMaxConnectionAgeGrace(),
+	} {
+		if d == nil {
+			continue
+		}
+		if err := d.CheckValid(); err != nil {
+			return err
+		}
+		if d.AsDuration() <= 0 {
+			return errors.New(name + " must be greater than 0")
+		}
+	}
+	return nil
+}
+
+// GrpcServerOptions returns the gRPC server options from the given settings. Unset settings are
+// left to the gRPC defaults.
+func GrpcServerOptions(cfg *configv1.GrpcServer) []grpc.ServerOption {
+	if cfg == nil {
+		return nil
+	}
+
+	var opts []grpc.ServerOption
+	if cfg.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams))
+	}
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(cfg.MaxRecvMsgSize)))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(int(cfg.MaxSendMsgSize)))
+	}
+
+	params := keepalive.ServerParameters{
+		Time:                  cfg.GetKeepaliveTime().AsDuration(),
+		Timeout:               cfg.GetKeepaliveTimeout().AsDuration(),
+		MaxConnectionIdle:     cfg.GetMaxConnectionIdle().AsDuration(),
+		MaxConnectionAge:      cfg.GetMaxConnectionAge().AsDuration(),
+		MaxConnectionAgeGrace: cfg.GetMaxConnectionAgeGrace().AsDuration(),
+	}
+	if params != (keepalive.ServerParameters{}) {
+		opts = append(opts, grpc.KeepaliveParams(params))
+	}
+
+	if cfg.KeepaliveMinTime != nil || cfg.KeepalivePermitWithoutStream {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.GetKeepaliveMinTime().AsDuration(),
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		}))
+	}
+	return opts
+}
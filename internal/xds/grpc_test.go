@@ -0,0 +1,50 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestGrpcServerOptions(t *testing.T) {
+	require.Empty(t, xds.GrpcServerOptions(nil))
+	require.Empty(t, xds.GrpcServerOptions(&configv1.GrpcServer{}))
+
+	opts := xds.GrpcServerOptions(&configv1.GrpcServer{
+		MaxConcurrentStreams: 1000,
+		MaxRecvMsgSize:       1 << 20,
+		KeepaliveTime:        durationpb.New(30 * time.Second),
+		KeepaliveMinTime:     durationpb.New(10 * time.Second),
+	})
+	// max concurrent streams, max recv msg size, keepalive params and enforcement policy.
+	require.Len(t, opts, 4)
+}
+
+func TestValidateGrpcServer(t *testing.T) {
+	require.NoError(t, xds.ValidateGrpcServer(nil))
+	require.NoError(t, xds.ValidateGrpcServer(&configv1.GrpcServer{
+		MaxConnectionAge: durationpb.New(time.Hour),
+	}))
+	require.Error(t, xds.ValidateGrpcServer(&configv1.GrpcServer{
+		KeepaliveTimeout: durationpb.New(-time.Second),
+	}))
+}
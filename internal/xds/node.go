@@ -0,0 +1,66 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"fmt"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// DefaultNodeID is the ID of a node made for DefaultNodeGroup.
+const DefaultNodeID = "rundown"
+
+// NewNode returns a node that is a member of the given node group. The node ID and cluster are taken
+// from the first IDs and clusters of the node group match criteria, or default to the node group
+// name.
+func NewNode(cfg *configv1.Config, nodeGroup string) (*core.Node, error) {
+	groups, err := NewNodeGroups(cfg.NodeGroups)
+	if err != nil {
+		return nil, err
+	}
+	if !groups.Has(nodeGroup) {
+		return nil, fmt.Errorf("unknown node group %q", nodeGroup)
+	}
+
+	node := &core.Node{Id: DefaultNodeID, Cluster: DefaultNodeID}
+	for _, group := range cfg.NodeGroups {
+		if group.Name != nodeGroup {
+			continue
+		}
+		node.Id, node.Cluster = group.Name, group.Name
+		if ids := group.Match.GetIds(); len(ids) > 0 {
+			node.Id = ids[0]
+		}
+		if clusters := group.Match.GetClusters(); len(clusters) > 0 {
+			node.Cluster = clusters[0]
+		}
+		if metadata := group.Match.GetMetadata(); len(metadata) > 0 {
+			node.Metadata = &structpb.Struct{Fields: make(map[string]*structpb.Value, len(metadata))}
+			for key, value := range metadata {
+				node.Metadata.Fields[key] = structpb.NewStringValue(value)
+			}
+		}
+	}
+
+	// A preceding node group possibly matches the node.
+	if matched := groups.ID(node); matched != nodeGroup {
+		return nil, fmt.Errorf("the node made for node group %q matches node group %q", nodeGroup, matched)
+	}
+	return node, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// DefaultNodeGroup is the name of the group for the nodes that match no configured node group.
+const DefaultNodeGroup = "default"
+
+// NodeGroups is a cache.NodeHash implementation that hashes a node to the name of the first node
+// group it matches, or to DefaultNodeGroup. Hence, the snapshots are keyed by node group names.
+type NodeGroups struct {
+	mu     sync.RWMutex
+	groups []*configv1.NodeGroup
+}
+
+var _ cache.NodeHash = (*NodeGroups)(nil)
+
+// NewNodeGroups returns a new NodeGroups with the given groups.
+func NewNodeGroups(groups []*configv1.NodeGroup) (*NodeGroups, error) {
+	n := &NodeGroups{}
+	if err := n.Update(groups); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Update replaces the current node groups with the given groups.
+func (n *NodeGroups) Update(groups []*configv1.NodeGroup) error {
+	if err := ValidateNodeGroups(groups); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.groups = groups
+	return nil
+}
+
+// ID returns the name of the node group the node belongs to.
+func (n *NodeGroups) ID(node *core.Node) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, group := range n.groups {
+		if Matches(group.Match, node) {
+			return group.Name
+		}
+	}
+	return DefaultNodeGroup
+}
+
+// Has returns true when the given name is DefaultNodeGroup or one of the node group names.
+func (n *NodeGroups) Has(name string) bool {
+	if name == DefaultNodeGroup {
+		return true
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, group := range n.groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns DefaultNodeGroup and the node group names.
+func (n *NodeGroups) Names() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	names := make([]string, 0, len(n.groups)+1)
+	names = append(names, DefaultNodeGroup)
+	for _, group := range n.groups {
+		names = append(names, group.Name)
+	}
+	return names
+}
+
+// ValidateNodeGroups makes sure the node groups have unique names, and none of them is named as
+// DefaultNodeGroup.
+func ValidateNodeGroups(groups []*configv1.NodeGroup) error {
+	names := make(map[string]struct{}, len(groups))
+	for i, group := range groups {
+		if group.Name == "" {
+			return fmt.Errorf("node group at index %d: name is required", i)
+		}
+		if group.Name == DefaultNodeGroup {
+			return fmt.Errorf("node group at index %d: name %q is reserved", i, DefaultNodeGroup)
+		}
+		if _, ok := names[group.Name]; ok {
+			return fmt.Errorf("node group at index %d: duplicate name %q", i, group.Name)
+		}
+		names[group.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Matches returns true when the node matches all the specified criteria.
+func Matches(match *configv1.NodeMatch, node *core.Node) bool {
+	if node == nil {
+		return false
+	}
+	if match == nil {
+		return true
+	}
+	if len(match.Ids) > 0 && !contains(match.Ids, node.Id) {
+		return false
+	}
+	if len(match.Clusters) > 0 && !contains(match.Clusters, node.Cluster) {
+		return false
+	}
+	for key, value := range match.Metadata {
+		field, ok := node.GetMetadata().GetFields()[key]
+		if !ok || field.GetStringValue() != value {
+			return false
+		}
+	}
+	return true
+}
+
+// NewSnapshots returns consistent snapshots keyed by node group names, built from the config. The
+// snapshot for the nodes that match no group is keyed by DefaultNodeGroup.
+func NewSnapshots(version string, cfg *configv1.Config) (map[string]*cache.Snapshot, error) {
+	return NewSnapshotsWith(version, cfg, nil)
+}
+
+// NewSnapshotsWith returns snapshots like NewSnapshots, with the given extra resources keyed by node
+// group names. An extra resource replaces the configured resource of the same type and name.
+func NewSnapshotsWith(version string, cfg *configv1.Config,
+	extra map[string]map[resource.Type][]types.Resource) (map[string]*cache.Snapshot, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if err := ValidateNodeGroups(cfg.NodeGroups); err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[string]*cache.Snapshot, len(cfg.NodeGroups)+1)
+	snapshot, err := newSnapshot(version, cfg.Resources, cfg.ResourcesDir, extra[DefaultNodeGroup])
+	if err != nil {
+		return nil, fmt.Errorf("node group %q: %w", DefaultNodeGroup, err)
+	}
+	snapshots[DefaultNodeGroup] = snapshot
+
+	for _, group := range cfg.NodeGroups {
+		if snapshot, err = newSnapshot(version, group.Resources, group.ResourcesDir, extra[group.Name]); err != nil {
+			return nil, fmt.Errorf("node group %q: %w", group.Name, err)
+		}
+		snapshots[group.Name] = snapshot
+	}
+	return snapshots, nil
+}
+
+// WatchedPaths returns the paths of the files and directories the snapshots are built from, i.e.
+// the resource directories and the files of the file secrets.
+func WatchedPaths(cfg *configv1.Config) []string {
+	var paths []string
+	add := func(r *configv1.Resources, dir string) {
+		if dir != "" {
+			paths = append(paths, dir)
+		}
+		for _, secret := range r.GetFileSecrets() {
+			paths = append(paths, SecretPaths(secret)...)
+		}
+	}
+	add(cfg.Resources, cfg.ResourcesDir)
+	for _, group := range cfg.NodeGroups {
+		add(group.Resources, group.ResourcesDir)
+	}
+	return paths
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	runtime "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestNodeGroups(t *testing.T) {
+	groups, err := xds.NewNodeGroups([]*configv1.NodeGroup{
+		{Name: "by-id", Match: &configv1.NodeMatch{Ids: []string{"a", "b"}}},
+		{Name: "by-cluster-and-metadata", Match: &configv1.NodeMatch{
+			Clusters: []string{"ingress"},
+			Metadata: map[string]string{"zone": "west"},
+		}},
+		{Name: "by-cluster", Match: &configv1.NodeMatch{Clusters: []string{"ingress"}}},
+	})
+	require.NoError(t, err)
+
+	metadata, err := structpb.NewStruct(map[string]interface{}{"zone": "west"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		node     *core.Node
+		expected string
+	}{
+		{&core.Node{Id: "a", Cluster: "ingress", Metadata: metadata}, "by-id"},
+		{&core.Node{Id: "c", Cluster: "ingress", Metadata: metadata}, "by-cluster-and-metadata"},
+		{&core.Node{Id: "c", Cluster: "ingress"}, "by-cluster"},
+		{&core.Node{Id: "c", Cluster: "egress"}, xds.DefaultNodeGroup},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, groups.ID(test.node))
+	}
+
+	require.Error(t, groups.Update([]*configv1.NodeGroup{{Name: xds.DefaultNodeGroup}}))
+	require.Error(t, groups.Update([]*configv1.NodeGroup{{Name: "a"}, {Name: "a"}}))
+	require.Error(t, groups.Update([]*configv1.NodeGroup{{}}))
+}
+
+func TestNewNode(t *testing.T) {
+	cfg := &configv1.Config{NodeGroups: []*configv1.NodeGroup{
+		{Name: "by-id", Match: &configv1.NodeMatch{Ids: []string{"a", "b"}}},
+		{Name: "by-metadata", Match: &configv1.NodeMatch{Metadata: map[string]string{"zone": "west"}}},
+		{Name: "by-cluster", Match: &configv1.NodeMatch{Clusters: []string{"ingress"}}},
+	}}
+
+	node, err := xds.NewNode(cfg, "by-id")
+	require.NoError(t, err)
+	require.Equal(t, "a", node.Id)
+
+	node, err = xds.NewNode(cfg, "by-metadata")
+	require.NoError(t, err)
+	require.Equal(t, "west", node.Metadata.Fields["zone"].GetStringValue())
+
+	node, err = xds.NewNode(cfg, xds.DefaultNodeGroup)
+	require.NoError(t, err)
+	require.Equal(t, xds.DefaultNodeID, node.Id)
+
+	_, err = xds.NewNode(cfg, "unknown")
+	require.Error(t, err)
+
+	// A node group that matches any node takes all of the nodes made for the next groups.
+	cfg.NodeGroups = append([]*configv1.NodeGroup{{Name: "all"}}, cfg.NodeGroups...)
+	_, err = xds.NewNode(cfg, "by-id")
+	require.Error(t, err)
+	_, err = xds.NewNode(cfg, xds.DefaultNodeGroup)
+	require.Error(t, err)
+}
+
+func TestNewSnapshotsWith(t *testing.T) {
+	layer := func(name, value string) *runtime.Runtime {
+		values, err := structpb.NewStruct(map[string]interface{}{"key": value})
+		require.NoError(t, err)
+		return &runtime.Runtime{Name: name, Layer: values}
+	}
+	configured := &configv1.Resources{RuntimeLayers: []*configv1.RuntimeLayer{
+		{Name: "a", Layer: layer("a", "configured").Layer},
+		{Name: "b", Layer: layer("b", "configured").Layer},
+	}}
+	cfg := &configv1.Config{
+		Resources:  configured,
+		NodeGroups: []*configv1.NodeGroup{{Name: "edge", Resources: configured}},
+	}
+
+	tests := []struct {
+		name  string
+		extra map[string]map[resource.Type][]types.Resource
+		// The expected runtime layer values, keyed by node group names, then by layer names.
+		expected map[string]map[string]string
+	}{
+		{
+			name: "no extra resources",
+			expected: map[string]map[string]string{
+				xds.DefaultNodeGroup: {"a": "configured", "b": "configured"},
+				"edge":               {"a": "configured", "b": "configured"},
+			},
+		},
+		{
+			name: "replace and add",
+			extra: map[string]map[resource.Type][]types.Resource{
+				xds.DefaultNodeGroup: {resource.RuntimeType: {layer("a", "extra"), layer("c", "extra")}},
+			},
+			expected: map[string]map[string]string{
+				xds.DefaultNodeGroup: {"a": "extra", "b": "configured", "c": "extra"},
+				"edge":               {"a": "configured", "b": "configured"},
+			},
+		},
+		{
+			name: "node groups",
+			extra: map[string]map[resource.Type][]types.Resource{
+				"edge":    {resource.RuntimeType: {layer("b", "extra")}},
+				"unknown": {resource.RuntimeType: {layer("a", "extra")}},
+			},
+			expected: map[string]map[string]string{
+				xds.DefaultNodeGroup: {"a": "configured", "b": "configured"},
+				"edge":               {"a": "configured", "b": "extra"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshots, err := xds.NewSnapshotsWith("1", cfg, test.extra)
+			require.NoError(t, err)
+			require.Len(t, snapshots, len(test.expected))
+			for group, expected := range test.expected {
+				layers := make(map[string]string)
+				for name, r := range snapshots[group].GetResources(resource.RuntimeType) {
+					layers[name] = r.(*runtime.Runtime).Layer.Fields["key"].GetStringValue()
+				}
+				require.Equal(t, expected, layers, group)
+			}
+		})
+	}
+	// The configured resources are untouched.
+	require.Len(t, configured.RuntimeLayers, 2)
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Redacted replaces the inlined key material of a redacted secret.
+const Redacted = "[redacted]"
+
+// Redact returns the resource with the inlined key material of a secret, e.g. a private key, replaced
+// by Redacted. The resource is cloned before redacted, while a resource other than a secret is
+// returned as is.
+func Redact(r types.Resource) types.Resource {
+	secret, ok := r.(*tls.Secret)
+	if !ok {
+		return r
+	}
+	redacted := proto.Clone(secret).(*tls.Secret)
+	switch t := redacted.Type.(type) {
+	case *tls.Secret_TlsCertificate:
+		redactSource(t.TlsCertificate.GetPrivateKey())
+		redactSource(t.TlsCertificate.GetPkcs12())
+		redactSource(t.TlsCertificate.GetPassword())
+	case *tls.Secret_SessionTicketKeys:
+		for _, key := range t.SessionTicketKeys.GetKeys() {
+			redactSource(key)
+		}
+	case *tls.Secret_GenericSecret:
+		redactSource(t.GenericSecret.GetSecret())
+	}
+	return redacted
+}
+
+// redactSource redacts an inlined data source. A data source referring to a file or an environment
+// variable is kept.
+func redactSource(s *core.DataSource) {
+	switch s.GetSpecifier().(type) {
+	case *core.DataSource_InlineBytes, *core.DataSource_InlineString:
+		s.Specifier = &core.DataSource_InlineString{InlineString: Redacted}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/testutil"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestRedact(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	testutil.WriteKeyPair(t, certFile, keyFile, 1)
+	key, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	secret, err := xds.LoadSecret(&configv1.FileSecret{Name: "server", CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	redacted := xds.Redact(secret).(*tls.Secret)
+
+	b, err := protojson.Marshal(redacted)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "PRIVATE KEY")
+	require.Equal(t, xds.Redacted, redacted.GetTlsCertificate().GetPrivateKey().GetInlineString())
+	// The certificate chain is kept, and the original secret is untouched.
+	require.True(t, proto.Equal(secret.GetTlsCertificate().GetCertificateChain(), redacted.GetTlsCertificate().GetCertificateChain()))
+	require.Equal(t, key, secret.GetTlsCertificate().GetPrivateKey().GetInlineBytes())
+
+	generic := xds.Redact(&tls.Secret{
+		Name: "token",
+		Type: &tls.Secret_GenericSecret{GenericSecret: &tls.GenericSecret{Secret: &core.DataSource{Specifier: &core.DataSource_InlineString{InlineString: "s3cr3t"}}}},
+	}).(*tls.Secret)
+	require.Equal(t, xds.Redacted, generic.GetGenericSecret().GetSecret().GetInlineString())
+
+	// Resources other than secrets are returned as is.
+	value := wrapperspb.String("value")
+	require.Same(t, value, xds.Redact(value))
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"fmt"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"                     // added to resolve v3.Router.
+	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3" // added to resolve v3.HttpConnectionManager.
+	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"               // added to resolve v3.TcpProxy.
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3" // added to resolve v3.HttpProtocolOptions.
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// validator is implemented by messages generated by protoc-gen-validate.
+type validator interface {
+	ValidateAll() error
+}
+
+// ParseResources parses the given resources config into typed and validated xDS resources, grouped
+// by their type URLs.
+func ParseResources(r *configv1.Resources) (map[resource.Type][]types.Resource, error) {
+	resources := make(map[resource.Type][]types.Resource)
+	if r == nil {
+		return resources, nil
+	}
+
+	entries := []struct {
+		typeURL resource.Type
+		values  []*structpb.Struct
+		newFunc func() types.Resource
+	}{
+		{resource.ClusterType, r.Clusters, func() types.Resource { return &cluster.Cluster{} }},
+		{resource.EndpointType, r.Endpoints, func() types.Resource { return &endpoint.ClusterLoadAssignment{} }},
+		{resource.RouteType, r.Routes, func() types.Resource { return &route.RouteConfiguration{} }},
+		{resource.ListenerType, r.Listeners, func() types.Resource { return &listener.Listener{} }},
+		{resource.SecretType, r.Secrets, func() types.Resource { return &tls.Secret{} }},
+	}
+
+	for _, entry := range entries {
+		for i, value := range entry.values {
+			parsed := entry.newFunc()
+			if err := fromStruct(value, parsed); err != nil {
+				return nil, fmt.Errorf("invalid %s at index %d: %w", entry.typeURL, i, err)
+			}
+			resources[entry.typeURL] = append(resources[entry.typeURL], parsed)
+		}
+	}
+	for i, fileSecret := range r.FileSecrets {
+		secret, err := LoadSecret(fileSecret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file secret at index %d: %w", i, err)
+		}
+		resources[resource.SecretType] = append(resources[resource.SecretType], secret)
+	}
+	runtimes, err := ParseRuntimeLayers(r.RuntimeLayers)
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range runtimes {
+		resources[resource.RuntimeType] = append(resources[resource.RuntimeType], layer)
+	}
+	return resources, nil
+}
+
+// NewSnapshot returns a consistent snapshot with the given version, built from the resources config.
+func NewSnapshot(version string, r *configv1.Resources) (*cache.Snapshot, error) {
+	return newSnapshot(version, r, "", nil)
+}
+
+// newSnapshot returns a consistent snapshot with the given version, built from the resources config,
+// the resource files in dir when it is set, and the extra resources.
+func newSnapshot(version string, r *configv1.Resources, dir string,
+	extra map[resource.Type][]types.Resource) (*cache.Snapshot, error) {
+	resources, err := ParseResources(r)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		loaded, err := LoadResources(dir)
+		if err != nil {
+			return nil, err
+		}
+		mergeResources(resources, loaded)
+	}
+	ReplaceResources(resources, extra)
+	snapshot, err := cache.NewSnapshot(version, resources)
+	if err != nil {
+		return nil, err
+	}
+	if err = snapshot.Consistent(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// fromStruct converts the generic struct value to the target message, and validates it.
+func fromStruct(value *structpb.Struct, target proto.Message) error {
+	b, err := protojson.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err = protojson.Unmarshal(b, target); err != nil {
+		return err
+	}
+	if v, ok := target.(validator); ok {
+		return v.ValidateAll()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/xds"
+)
+
+//go:embed testdata/resources.yaml
+var resourcesYAML []byte
+
+func TestNewSnapshot(t *testing.T) {
+	j, err := yaml.YAMLToJSON(resourcesYAML)
+	require.NoError(t, err)
+
+	var r configv1.Resources
+	require.NoError(t, protojson.Unmarshal(j, &r))
+
+	snapshot, err := xds.NewSnapshot("1", &r)
+	require.NoError(t, err)
+	require.Equal(t, "1", snapshot.GetVersion(resource.ClusterType))
+	require.Contains(t, snapshot.GetResources(resource.ClusterType), "example_proxy_cluster")
+	require.Contains(t, snapshot.GetResources(resource.RouteType), "local_route")
+	require.Contains(t, snapshot.GetResources(resource.ListenerType), "listener_0")
+
+	// The listener refers to a missing route configuration.
+	r.Routes = nil
+	_, err = xds.NewSnapshot("2", &r)
+	require.Error(t, err)
+
+	// The cluster has an invalid connect timeout.
+	invalid, err := structpb.NewStruct(map[string]interface{}{"name": "invalid", "connect_timeout": "-1s"})
+	require.NoError(t, err)
+	_, err = xds.NewSnapshot("3", &configv1.Resources{Clusters: []*structpb.Struct{invalid}})
+	require.Error(t, err)
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"errors"
+	"fmt"
+
+	runtime "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// ParseRuntimeLayers converts the runtime layers config into runtime resources.
+func ParseRuntimeLayers(layers []*configv1.RuntimeLayer) ([]*runtime.Runtime, error) {
+	names := make(map[string]struct{}, len(layers))
+	runtimes := make([]*runtime.Runtime, 0, len(layers))
+	for i, layer := range layers {
+		if layer.Name == "" {
+			return nil, fmt.Errorf("invalid runtime layer at index %d: %w", i, errors.New("name is required"))
+		}
+		if _, ok := names[layer.Name]; ok {
+			return nil, fmt.Errorf("invalid runtime layer at index %d: duplicate name %q", i, layer.Name)
+		}
+		names[layer.Name] = struct{}{}
+
+		values := layer.Layer
+		if values == nil {
+			values = &structpb.Struct{}
+		}
+		r := &runtime.Runtime{Name: layer.Name, Layer: values}
+		if err := r.ValidateAll(); err != nil {
+			return nil, fmt.Errorf("invalid runtime layer at index %d: %w", i, err)
+		}
+		runtimes = append(runtimes, r)
+	}
+	return runtimes, nil
+}
+
+// RuntimeOverrides holds the runtime values that override the configured runtime layers, keyed by
+// node group names, then by layer names, then by runtime keys.
+type RuntimeOverrides map[string]map[string]map[string]*structpb.Value
+
+// Set sets the values of the runtime keys in the layer of the node group. A nil value removes the
+// override of the key.
+func (o RuntimeOverrides) Set(nodeGroup, layer string, values map[string]*structpb.Value) {
+	layers, ok := o[nodeGroup]
+	if !ok {
+		layers = make(map[string]map[string]*structpb.Value)
+		o[nodeGroup] = layers
+	}
+	keys, ok := layers[layer]
+	if !ok {
+		keys = make(map[string]*structpb.Value)
+		layers[layer] = keys
+	}
+	for key, value := range values {
+		if value == nil {
+			delete(keys, key)
+			continue
+		}
+		keys[key] = value
+	}
+	if len(keys) == 0 {
+		delete(layers, layer)
+	}
+	if len(layers) == 0 {
+		delete(o, nodeGroup)
+	}
+}
+
+// Apply returns a copy of the config with the overrides merged into the runtime layers of the node
+// groups. A missing layer is added. The overrides of unknown node groups are ignored.
+func (o RuntimeOverrides) Apply(cfg *configv1.Config) *configv1.Config {
+	if len(o) == 0 || cfg == nil {
+		return cfg
+	}
+
+	applied := proto.Clone(cfg).(*configv1.Config)
+	if layers, ok := o[DefaultNodeGroup]; ok {
+		applied.Resources = applyRuntimeLayers(applied.Resources, layers)
+	}
+	for _, group := range applied.NodeGroups {
+		if layers, ok := o[group.Name]; ok {
+			group.Resources = applyRuntimeLayers(group.Resources, layers)
+		}
+	}
+	return applied
+}
+
+func applyRuntimeLayers(r *configv1.Resources, layers map[string]map[string]*structpb.Value) *configv1.Resources {
+	if r == nil {
+		r = &configv1.Resources{}
+	}
+	for name, keys := range layers {
+		var layer *configv1.RuntimeLayer
+		for _, l := range r.RuntimeLayers {
+			if l.Name == name {
+				layer = l
+				break
+			}
+		}
+		if layer == nil {
+			layer = &configv1.RuntimeLayer{Name: name}
+			r.RuntimeLayers = append(r.RuntimeLayers, layer)
+		}
+		if layer.Layer == nil {
+			layer.Layer = &structpb.Struct{}
+		}
+		if layer.Layer.Fields == nil {
+			layer.Layer.Fields = make(map[string]*structpb.Value, len(keys))
+		}
+		for key, value := range keys {
+			layer.Layer.Fields[key] = value
+		}
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"testing"
+
+	runtime "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestParseRuntimeLayers(t *testing.T) {
+	layer, err := structpb.NewStruct(map[string]interface{}{"envoy.reloadable_features.foo": false})
+	require.NoError(t, err)
+	runtimes, err := xds.ParseRuntimeLayers([]*configv1.RuntimeLayer{{Name: "rtds", Layer: layer}, {Name: "empty"}})
+	require.NoError(t, err)
+	require.Len(t, runtimes, 2)
+
+	_, err = xds.ParseRuntimeLayers([]*configv1.RuntimeLayer{{Layer: layer}})
+	require.Error(t, err)
+	_, err = xds.ParseRuntimeLayers([]*configv1.RuntimeLayer{{Name: "rtds"}, {Name: "rtds"}})
+	require.Error(t, err)
+}
+
+func TestRuntimeOverrides(t *testing.T) {
+	layer, err := structpb.NewStruct(map[string]interface{}{"a": 1, "b": "x"})
+	require.NoError(t, err)
+	cfg := &configv1.Config{
+		Resources:  &configv1.Resources{RuntimeLayers: []*configv1.RuntimeLayer{{Name: "rtds", Layer: layer}}},
+		NodeGroups: []*configv1.NodeGroup{{Name: "canary"}},
+	}
+
+	overrides := make(xds.RuntimeOverrides)
+	require.Same(t, cfg, overrides.Apply(cfg))
+
+	overrides.Set(xds.DefaultNodeGroup, "rtds", map[string]*structpb.Value{
+		"a": structpb.NewNumberValue(2),
+		"c": structpb.NewBoolValue(true),
+	})
+	overrides.Set("canary", "flags", map[string]*structpb.Value{"d": structpb.NewStringValue("y")})
+	overrides.Set("unknown", "flags", map[string]*structpb.Value{"e": structpb.NewStringValue("z")})
+
+	snapshots, err := xds.NewSnapshots("1", overrides.Apply(cfg))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+
+	values := runtimeValues(t, snapshots[xds.DefaultNodeGroup].GetResources(resource.RuntimeType), "rtds")
+	require.Equal(t, map[string]interface{}{"a": float64(2), "b": "x", "c": true}, values)
+	values = runtimeValues(t, snapshots["canary"].GetResources(resource.RuntimeType), "flags")
+	require.Equal(t, map[string]interface{}{"d": "y"}, values)
+
+	// The config is untouched.
+	require.Equal(t, float64(1), cfg.Resources.RuntimeLayers[0].Layer.AsMap()["a"])
+
+	// Removing all the overrides of a layer removes it.
+	overrides.Set("canary", "flags", map[string]*structpb.Value{"d": nil})
+	snapshots, err = xds.NewSnapshots("2", overrides.Apply(cfg))
+	require.NoError(t, err)
+	require.Empty(t, snapshots["canary"].GetResources(resource.RuntimeType))
+}
+
+func runtimeValues(t *testing.T, resources map[string]types.Resource, name string) map[string]interface{} {
+	r, ok := resources[name].(*runtime.Runtime)
+	require.True(t, ok)
+	return r.Layer.AsMap()
+}
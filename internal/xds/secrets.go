@@ -0,0 +1,146 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+)
+
+// The file names in a FileSecret directory, following the keys of a Kubernetes TLS secret.
+const (
+	secretCertFile = "tls.crt"
+	secretKeyFile  = "tls.key"
+	secretCAFile   = "ca.crt"
+)
+
+// LoadSecret loads the secret from its files. The content of the files is inlined, since the files
+// are not necessarily available to the nodes, unless the secret is set to refer to the files by path.
+// The files are read and verified either way.
+func LoadSecret(s *configv1.FileSecret) (*tls.Secret, error) {
+	certFile, keyFile, caFile, err := secretFiles(s)
+	if err != nil {
+		return nil, err
+	}
+	source := inlineBytes
+	if s.ByPath {
+		source = filename
+	}
+
+	if certFile != "" {
+		cert, err := os.ReadFile(certFile) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+		key, err := os.ReadFile(keyFile) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+		// Make sure the pair matches, e.g. the files are not in the middle of a rotation.
+		if _, err = cryptotls.X509KeyPair(cert, key); err != nil {
+			return nil, fmt.Errorf("invalid key pair: %w", err)
+		}
+		return &tls.Secret{
+			Name: s.Name,
+			Type: &tls.Secret_TlsCertificate{
+				TlsCertificate: &tls.TlsCertificate{
+					CertificateChain: source(certFile, cert),
+					PrivateKey:       source(keyFile, key),
+				},
+			},
+		}, nil
+	}
+
+	ca, err := os.ReadFile(caFile) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no valid ca certificates in %s", caFile)
+	}
+	return &tls.Secret{
+		Name: s.Name,
+		Type: &tls.Secret_ValidationContext{
+			ValidationContext: &tls.CertificateValidationContext{
+				TrustedCa: source(caFile, ca),
+			},
+		},
+	}, nil
+}
+
+// SecretPaths returns the paths to be watched for the changes of the secret.
+func SecretPaths(s *configv1.FileSecret) []string {
+	if s.Dir != "" {
+		return []string{s.Dir}
+	}
+	var paths []string
+	for _, path := range []string{s.CertFile, s.KeyFile, s.CaFile} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// secretFiles returns either the cert and key files, or the CA file of the secret.
+func secretFiles(s *configv1.FileSecret) (certFile, keyFile, caFile string, err error) {
+	if s.Name == "" {
+		return "", "", "", errors.New("name is required")
+	}
+
+	if s.Dir != "" {
+		if s.CertFile != "" || s.KeyFile != "" || s.CaFile != "" {
+			return "", "", "", errors.New("dir can't be set together with the files")
+		}
+		certFile, keyFile = filepath.Join(s.Dir, secretCertFile), filepath.Join(s.Dir, secretKeyFile)
+		if _, err = os.Stat(certFile); err == nil {
+			return certFile, keyFile, "", nil
+		}
+		return "", "", filepath.Join(s.Dir, secretCAFile), nil
+	}
+
+	switch {
+	case s.CertFile != "" && s.KeyFile != "" && s.CaFile != "":
+		return "", "", "", errors.New("ca file can't be set together with cert file and key file")
+	case s.CertFile != "" && s.KeyFile != "":
+		return s.CertFile, s.KeyFile, "", nil
+	case s.CertFile != "" || s.KeyFile != "":
+		return "", "", "", errors.New("both cert file and key file are required")
+	case s.CaFile != "":
+		return "", "", s.CaFile, nil
+	}
+	return "", "", "", errors.New("either dir, cert file and key file, or ca file is required")
+}
+
+func inlineBytes(_ string, b []byte) *core.DataSource {
+	return &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: b}}
+}
+
+// filename refers to the file by its absolute path, since the nodes may run in other directories.
+func filename(path string, _ []byte) *core.DataSource {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return &core.DataSource{Specifier: &core.DataSource_Filename{Filename: path}}
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Dhi Aurrahman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/dio/rundown/generated/xds/config/v1"
+	"github.com/dio/rundown/internal/testutil"
+	"github.com/dio/rundown/internal/xds"
+)
+
+func TestLoadSecret(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	testutil.WriteKeyPair(t, certFile, keyFile, 1)
+
+	secret, err := xds.LoadSecret(&configv1.FileSecret{Name: "server", CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.GetTlsCertificate().GetCertificateChain().GetInlineBytes())
+	require.NotEmpty(t, secret.GetTlsCertificate().GetPrivateKey().GetInlineBytes())
+
+	secret, err = xds.LoadSecret(&configv1.FileSecret{Name: "ca", CaFile: certFile})
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.GetValidationContext().GetTrustedCa().GetInlineBytes())
+
+	// A directory with tls.crt and tls.key is served as a certificate.
+	secret, err = xds.LoadSecret(&configv1.FileSecret{Name: "server", Dir: dir})
+	require.NoError(t, err)
+	require.NotNil(t, secret.GetTlsCertificate())
+
+	// Referring to the files by path keeps the key material out of the secret.
+	secret, err = xds.LoadSecret(&configv1.FileSecret{Name: "server", Dir: dir, ByPath: true})
+	require.NoError(t, err)
+	require.Equal(t, certFile, secret.GetTlsCertificate().GetCertificateChain().GetFilename())
+	require.Equal(t, keyFile, secret.GetTlsCertificate().GetPrivateKey().GetFilename())
+	require.Empty(t, secret.GetTlsCertificate().GetPrivateKey().GetInlineBytes())
+	secret, err = xds.LoadSecret(&configv1.FileSecret{Name: "ca", CaFile: certFile, ByPath: true})
+	require.NoError(t, err)
+	require.Equal(t, certFile, secret.GetValidationContext().GetTrustedCa().GetFilename())
+
+	tests := []struct {
+		name   string
+		secret *configv1.FileSecret
+	}{
+		{"missing name", &configv1.FileSecret{CaFile: certFile}},
+		{"missing files", &configv1.FileSecret{Name: "a"}},
+		{"missing key file", &configv1.FileSecret{Name: "a", CertFile: certFile}},
+		{"dir and files", &configv1.FileSecret{Name: "a", Dir: dir, CaFile: certFile}},
+		{"mismatched key pair", &configv1.FileSecret{Name: "a", CertFile: certFile, KeyFile: certFile}},
+		{"invalid ca", &configv1.FileSecret{Name: "a", CaFile: keyFile}},
+		{"mismatched key pair by path", &configv1.FileSecret{Name: "a", CertFile: certFile, KeyFile: certFile, ByPath: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := xds.LoadSecret(test.secret)
+			require.Error(t, err)
+		})
+	}
+
+	snapshots, err := xds.NewSnapshots("1", &configv1.Config{
+		Resources: &configv1.Resources{
+			FileSecrets: []*configv1.FileSecret{{Name: "server", Dir: dir}, {Name: "ca", CaFile: certFile}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots[xds.DefaultNodeGroup].GetResources(resource.SecretType), 2)
+}